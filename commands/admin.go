@@ -0,0 +1,91 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/viper"
+)
+
+// The admin endpoints change how daedalus creates mazes while it is running.
+// They need the admin key in the X-Admin-Key header, and without an
+// admin key configured they are disabled.
+//
+//	GET  /admin/settings  the settings new mazes are created with
+//	PUT  /admin/settings  changes some of them, mazes being solved keep theirs
+//	POST /admin/reset     clears the scores
+
+func addAdminRoutes(admin *routeGroup) {
+	admin.GET("/settings", GetSettings)
+	admin.PUT("/settings", PutSettings)
+	admin.POST("/reset", ResetScores)
+}
+
+// Only lets requests through which carry the admin key in their X-Admin-Key header
+func requireAdminKey(c *Context) {
+	key := viper.GetString("admin-key")
+	if key == "" {
+		respond(c, http.StatusForbidden, mazelib.Reply{Error: true, Message: "admin endpoints are disabled, start daedalus with an admin key"})
+		c.Abort()
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(key), []byte(c.GetHeader("X-Admin-Key"))) != 1 {
+		respond(c, http.StatusUnauthorized, mazelib.Reply{Error: true, Message: "wrong admin key"})
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// The API response to GET /admin/settings
+func GetSettings(c *Context) {
+	respond(c, http.StatusOK, currentSettings())
+}
+
+// The API response to PUT /admin/settings.
+// Settings missing in the request are left as they are.
+func PutSettings(c *Context) {
+	s := currentSettings()
+	if err := c.BindJSON(&s); err != nil {
+		// BindJSON already replied with 400
+		return
+	}
+	if err := s.validate(); err != nil {
+		respond(c, http.StatusBadRequest, mazelib.Reply{Error: true, Message: err.Error()})
+		return
+	}
+
+	settingsMu.Lock()
+	changedSettings = &s
+	settingsMu.Unlock()
+
+	respond(c, http.StatusOK, s)
+}
+
+// The API response to POST /admin/reset.
+// The results stored so far are moved aside to a file named after the time
+// of the reset rather than thrown away.
+func ResetScores(c *Context) {
+	games.resetScores()
+	if err := store.reset(); err != nil {
+		respond(c, http.StatusInternalServerError, mazelib.Reply{Error: true, Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,292 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib/gen"
+	"bitbucket.org/mannih/gc6/mazelib/metrics"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the bench command.
+// This will be called as 'laybrinth bench'
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure how fast mazes are generated and solved",
+	Long: `Bench times how long Daedalus takes to generate laybrinths of every
+  algorithm in a range of sizes and what they are like, and how many steps
+  and how much time Icarus takes to solve them with every strategy.
+  Everything is repeated the times asked for and runs in this process. With
+  a --seed the same laybrinths are solved the same way every time.
+
+  With --protocols, like http1,h2c, Icarus also solves the mazes on a
+  Daedalus listening on the loopback speaking each of them, a move per
+  request, to compare how long a move takes over each.
+
+  The results are printed as tables, and written to --out as JSON if set.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := RunBench(viper.GetString("out")); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	benchCmd.Flags().Int64("seed", 0, "seed the laybrinths and the choices of the strategies follow from (default is a random one)")
+	benchCmd.Flags().StringP("out", "o", "", "file the results are written to as JSON")
+	benchCmd.Flags().StringSlice("protocols", nil, "protocols to compare moving icarus over, like http1,h2c (default is not to compare them)")
+	RootCmd.AddCommand(benchCmd)
+}
+
+// the sizes mazes are generated in, both width and height
+var benchSizes = []int{10, 25, 50, 100}
+
+// benchmark is what bench measured
+type benchmark struct {
+	Generation []generationBench `json:"generation"`
+	Solving    []solvingBench    `json:"solving"`
+	Protocols  []protocolBench   `json:"protocols,omitempty"`
+}
+
+// generationBench is how long generating mazes of an algorithm and size took,
+// and what the mazes were like on average
+type generationBench struct {
+	Algorithm  string        `json:"algorithm"`
+	Width      int           `json:"width"`
+	Height     int           `json:"height"`
+	Mazes      int           `json:"mazes"`
+	Average    time.Duration `json:"average"`
+	DeadEnds   float64       `json:"dead_ends"`
+	Loops      float64       `json:"loops"`
+	Difficulty float64       `json:"difficulty"`
+}
+
+// solvingBench is how a strategy did solving the same mazes as the others
+type solvingBench struct {
+	Strategy     string        `json:"strategy"`
+	Mazes        int           `json:"mazes"`
+	Solved       int           `json:"solved"`
+	AverageSteps int           `json:"average_steps"`
+	AverageTime  time.Duration `json:"average_time"`
+}
+
+// protocolBench is how long a move over HTTP took icarus speaking a protocol,
+// sending one request per move
+type protocolBench struct {
+	Protocol string        `json:"protocol"`
+	Mazes    int           `json:"mazes"`
+	Moves    int           `json:"moves"`
+	PerMove  time.Duration `json:"per_move"`
+}
+
+// Runs the benchmarks, writing them to the file at out as JSON if it isn't empty
+func RunBench(out string) error {
+	settings := currentSettings()
+	if err := settings.validate(); err != nil {
+		return err
+	}
+	times := viper.GetInt("times")
+	if times < 1 {
+		times = 1
+	}
+
+	var b benchmark
+	for _, alg := range gen.Names() {
+		for _, size := range benchSizes {
+			s := settings
+			s.Algorithm, s.Width, s.Height = alg, size, size
+			mazes := make([]*Maze, times)
+			begun := time.Now()
+			for x := range mazes {
+				mazes[x] = createMaze(s)
+			}
+			g := generationBench{
+				Algorithm: alg,
+				Width:     size,
+				Height:    size,
+				Mazes:     times,
+				Average:   time.Since(begun) / time.Duration(times),
+			}
+			for _, m := range mazes {
+				mt := metrics.Measure(m)
+				g.DeadEnds += float64(mt.DeadEnds) / float64(times)
+				g.Loops += float64(mt.Loops) / float64(times)
+				g.Difficulty += mt.Difficulty / float64(times)
+				m.release()
+			}
+			b.Generation = append(b.Generation, g)
+		}
+	}
+
+	// every strategy solves the same mazes, and the same seed makes the
+	// same mazes and choices every time
+	seed := viper.GetInt64("seed")
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	seeds := make([]int64, times)
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
+	for _, name := range strategyNames {
+		l := newLocalGame("bench-"+name, name, settings)
+		sb := solvingBench{Strategy: name, Mazes: times}
+		var total time.Duration
+		for _, seed := range seeds {
+			strat, err := newStrategy(name, rand.New(rand.NewSource(seed)))
+			if err != nil {
+				return err
+			}
+			l.settings.Seed = seed
+			s, err := solveMaze(l, strat, settings.Width*settings.Height)
+			if err != nil {
+				return err
+			}
+			if s.Solved {
+				sb.Solved++
+			}
+			total += s.Duration
+		}
+		sb.AverageSteps = l.finish().AverageSteps
+		sb.AverageTime = total / time.Duration(times)
+		b.Solving = append(b.Solving, sb)
+	}
+	if protocols := viper.GetStringSlice("protocols"); len(protocols) > 0 {
+		pb, err := benchProtocols(protocols, settings, seeds)
+		if err != nil {
+			return err
+		}
+		b.Protocols = pb
+	}
+
+	if jsonOutput() {
+		printJSON(b)
+	} else {
+		b.print()
+	}
+	if out == "" {
+		return nil
+	}
+	j, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out, j, 0644)
+}
+
+// Prints a table of the generation times and one of how the strategies did
+func (b benchmark) print() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "algorithm\tsize\tmazes\ttime per maze\tdead ends\tloops\tdifficulty\t")
+	for _, g := range b.Generation {
+		fmt.Fprintf(w, "%s\t%dx%d\t%d\t%s\t%.1f\t%.1f\t%.1f\t\n", g.Algorithm, g.Width, g.Height, g.Mazes, g.Average, g.DeadEnds, g.Loops, g.Difficulty)
+	}
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "strategy\tsolved\tavg steps\ttime per maze\t")
+	for _, s := range b.Solving {
+		fmt.Fprintf(w, "%s\t%d/%d\t%d\t%s\t\n", s.Strategy, s.Solved, s.Mazes, s.AverageSteps, s.AverageTime)
+	}
+	w.Flush()
+	if len(b.Protocols) == 0 {
+		return
+	}
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "protocol\tmazes\tmoves\ttime per move\t")
+	for _, p := range b.Protocols {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t\n", p.Protocol, p.Mazes, p.Moves, p.PerMove)
+	}
+	w.Flush()
+}
+
+// Solves a maze for every seed with dfs on a daedalus listening on the
+// loopback, once speaking every protocol, and one move per request so the
+// protocols are all there is to compare.
+func benchProtocols(protocols []string, settings mazeSettings, seeds []int64) ([]protocolBench, error) {
+	r, err := newRouter(viper.GetString("router"), false)
+	if err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Handler: r, Protocols: serverProtocols()}
+	go srv.Serve(l)
+	defer srv.Close()
+
+	// icarus talks to this daedalus until the protocols are compared
+	savedIcarus, savedDaedalus, savedTransport := icarusConf, daedalusConf, transport
+	defer func() {
+		icarusConf, daedalusConf = savedIcarus, savedDaedalus
+		useTransport(savedTransport)
+		games.Lock()
+		games.quiet = false
+		games.Unlock()
+	}()
+	icarusConf.Server, icarusConf.Socket = "http://"+l.Addr().String(), ""
+	icarusConf.Transport, icarusConf.Batch = "http", false
+	daedalusConf.APIKeys = nil
+	games.Lock()
+	games.quiet = true
+	games.Unlock()
+
+	var benches []protocolBench
+	for _, name := range protocols {
+		p, err := clientProtocols(name)
+		if err != nil {
+			return nil, err
+		}
+		useTransport(newTransport(p, 4))
+
+		pb := protocolBench{Protocol: name, Mazes: len(seeds)}
+		var total time.Duration
+		sess := &session{}
+		for _, seed := range seeds {
+			strat, err := newStrategy("dfs", rand.New(rand.NewSource(seed)))
+			if err != nil {
+				return nil, err
+			}
+			s, err := solveMaze(sess, strat, settings.Width*settings.Height)
+			if err != nil {
+				return nil, err
+			}
+			pb.Moves += s.Steps + s.WallBumps
+			total += s.Duration
+		}
+		if pb.Moves > 0 {
+			pb.PerMove = total / time.Duration(pb.Moves)
+		}
+		benches = append(benches, pb)
+	}
+	return benches, nil
+}
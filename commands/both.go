@@ -0,0 +1,79 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the both command.
+// This will be called as 'laybrinth both'
+var bothCmd = &cobra.Command{
+	Use:   "both",
+	Short: "Run Daedalus and Icarus against each other in one process",
+	Long: `Both starts Daedalus and lets Icarus solve his laybrinths the times asked
+  for over a connection to localhost, then prints the results and the final
+  score of both and exits. It's what laybrinth does without a command, except
+  that Daedalus listens on a free port of localhost unless --addr says
+  otherwise, so several evaluations can run side by side.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if viper.GetString("addr") == "" {
+			viper.Set("addr", "127.0.0.1:0")
+		}
+		RunBoth()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(bothCmd)
+}
+
+// Runs daedalus and has icarus play against him until he is done.
+// Returns once both have printed their results.
+func RunBoth() {
+	// Listening before the server runs lets icarus connect right away,
+	// his requests wait for the server to get to them.
+	l, err := listen()
+	if err != nil {
+		daedalusLog.Error("couldn't listen", "err", err)
+		return
+	}
+	if _, ok := l.Addr().(*net.TCPAddr); ok {
+		// icarus finds the port daedalus got in addr
+		viper.Set("addr", l.Addr().String())
+		loadConfig()
+	}
+	serverListener = l
+
+	stopped := make(chan struct{})
+	go func() {
+		RunServer()
+		// in case the server didn't get to serve, icarus is left waiting otherwise
+		l.Close()
+		close(stopped)
+	}()
+
+	// daedalus knows more about the mazes, let him do the exporting
+	icarusExports = false
+	RunIcarus()
+
+	// wait for the server to print its results
+	Shutdown()
+	<-stopped
+}
@@ -0,0 +1,154 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"text/tabwriter"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the compare command.
+// This will be called as 'laybrinth compare'
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare strategies on the very same laybrinths",
+	Long: `Compare lets Icarus solve the same laybrinths with each of the strategies
+  asked for. The laybrinths follow from --seed, so a comparison can be
+  repeated, and as every strategy sees the same ones the steps they took can
+  be compared maze by maze.
+
+  Everything happens in this process, no server is started.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := compare(viper.GetStringSlice("strategies"), viper.GetInt("mazes"), viper.GetInt64("seed")); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	compareCmd.Flags().Int64("seed", 0, "seed the laybrinths follow from (default is a random one)")
+	compareCmd.Flags().StringSlice("strategies", nil, "strategies to compare (default is all of them)")
+	compareCmd.Flags().Int("mazes", 100, "number of laybrinths solved with each strategy")
+	RootCmd.AddCommand(compareCmd)
+}
+
+// pairing compares the steps two strategies took in the same mazes
+type pairing struct {
+	// the number of mazes the first, the second or neither took fewer steps in
+	First, Second, Ties int
+	// how many more steps the second took than the first
+	MeanDifference float64
+	StdDev         float64
+}
+
+// Compares the steps a and b took in the same mazes, in the same order
+func pair(a, b []int) pairing {
+	var p pairing
+	var diffs []float64
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			p.First++
+		case a[i] > b[i]:
+			p.Second++
+		default:
+			p.Ties++
+		}
+		diffs = append(diffs, float64(b[i]-a[i]))
+	}
+	if len(diffs) == 0 {
+		return p
+	}
+
+	for _, d := range diffs {
+		p.MeanDifference += d
+	}
+	p.MeanDifference /= float64(len(diffs))
+	for _, d := range diffs {
+		p.StdDev += (d - p.MeanDifference) * (d - p.MeanDifference)
+	}
+	p.StdDev = math.Sqrt(p.StdDev / float64(len(diffs)))
+	return p
+}
+
+// Solves the mazes following from the seed with each of the strategies and prints how they compare
+func compare(strategies []string, mazes int, seed int64) error {
+	settings := currentSettings()
+	if err := settings.validate(); err != nil {
+		return err
+	}
+	if len(strategies) == 0 {
+		strategies = strategyNames
+	}
+	for _, name := range strategies {
+		if _, err := newStrategy(name, nil); err != nil {
+			return err
+		}
+	}
+	if mazes < 1 {
+		return fmt.Errorf("there have to be mazes to compare on")
+	}
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	seeds := make([]int64, mazes)
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
+
+	steps := map[string][]int{}
+	for _, name := range strategies {
+		s, err := playSeeds(name, seeds, settings)
+		if err != nil {
+			return err
+		}
+		steps[name] = s
+	}
+
+	fmt.Printf("%d %dx%d mazes with seed %d\n", mazes, settings.Width, settings.Height, seed)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "strategy\tsolved\tavg steps\tmedian\tp90\tscore\t")
+	for _, name := range strategies {
+		st := mazelib.Statistics(steps[name], histogramBuckets)
+		score := scoring().Score(steps[name])
+		fmt.Fprintf(w, "%s\t%d/%d\t%d\t%d\t%d\t%d\t\n", name, score.Solved, mazes, st.Mean, st.Median, st.P90, score.Final)
+	}
+	w.Flush()
+
+	if len(strategies) < 2 {
+		return nil
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "first\tsecond\tfirst better\tsecond better\tties\tsecond - first\t")
+	for i, a := range strategies {
+		for _, b := range strategies[i+1:] {
+			p := pair(steps[a], steps[b])
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%+.1f ± %.1f\t\n", a, b, p.First, p.Second, p.Ties, p.MeanDifference, p.StdDev)
+		}
+	}
+	w.Flush()
+	return nil
+}
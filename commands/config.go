@@ -0,0 +1,169 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Every setting can be given as a flag, in the environment or in the config
+// file, a config.yaml in the working directory unless --config says
+// otherwise. The config command shows what each one ended up as and where
+// that came from, and writes config files to start from.
+
+// Defining the config command.
+// This will be called as 'laybrinth config'
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show the settings in effect",
+	Long: `Config lists every setting with the value in effect and where it came
+  from: a flag, the environment, the config file or the default.
+  Secrets like api keys are only shown as being set.
+
+  Every setting can go in the config file under the name of its flag, as in
+  'width: 30'. Environment variables are named like the flag in upper case.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if f := viper.ConfigFileUsed(); f != "" {
+			fmt.Println("config file:", f)
+		}
+		bindSettings()
+		printConfig(os.Stdout)
+	},
+}
+
+// This will be called as 'laybrinth config write [file]'
+var configWriteCmd = &cobra.Command{
+	Use:   "write [file]",
+	Short: "Write a config file with the settings in effect",
+	Long: `Write writes every setting with the value in effect, and what it does,
+  to a YAML config file to start from, config.yaml unless another file is
+  given. Existing files are left alone.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			cmd.Usage()
+			return
+		}
+		path := "config.yaml"
+		if len(args) == 1 {
+			path = args[0]
+		}
+		bindSettings()
+		if err := writeConfig(path); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("wrote", path)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configWriteCmd)
+	RootCmd.AddCommand(configCmd)
+}
+
+// settings which are only shown as being set
+var secrets = map[string]bool{"api-keys": true, "api-key": true, "admin-key": true, "leaderboard-secret": true}
+
+// Calls fn with every setting once, which are the flags shared by all
+// commands and those of each, except for --config itself
+func visitSettings(fn func(f *pflag.Flag)) {
+	seen := map[string]bool{"config": true}
+	visit := func(f *pflag.Flag) {
+		if !seen[f.Name] {
+			seen[f.Name] = true
+			fn(f)
+		}
+	}
+	RootCmd.PersistentFlags().VisitAll(visit)
+	for _, c := range RootCmd.Commands() {
+		c.LocalNonPersistentFlags().VisitAll(visit)
+	}
+}
+
+// Only the flags of the command running are bound, so the ones of the other
+// commands are bound here to show what they would be, the first of a
+// name standing for all of them
+func bindSettings() {
+	visitSettings(func(f *pflag.Flag) {
+		viper.BindPFlag(f.Name, f)
+	})
+}
+
+// Prints every setting with its value and where it came from
+func printConfig(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "setting\tvalue\tfrom")
+	visitSettings(func(f *pflag.Flag) {
+		v := configValue(f)
+		if secrets[f.Name] && len(viper.GetStringSlice(f.Name)) > 0 {
+			v = "(set)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Name, v, configSource(f))
+	})
+	w.Flush()
+}
+
+// Writes every setting to a new YAML file at path, each with its usage as a comment
+func writeConfig(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(f, "# laybrinth config, see 'laybrinth config' for what is in effect")
+	visitSettings(func(fl *pflag.Flag) {
+		fmt.Fprintf(f, "\n# %s\n%s: %s\n", fl.Usage, fl.Name, configValue(fl))
+	})
+	return f.Close()
+}
+
+// Returns the value of the setting as YAML
+func configValue(f *pflag.Flag) string {
+	switch f.Value.Type() {
+	case "string":
+		return strconv.Quote(viper.GetString(f.Name))
+	case "stringSlice":
+		var quoted []string
+		for _, s := range viper.GetStringSlice(f.Name) {
+			quoted = append(quoted, strconv.Quote(s))
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	case "duration":
+		return viper.GetDuration(f.Name).String()
+	}
+	return fmt.Sprint(viper.Get(f.Name))
+}
+
+// Tells where the value of the setting came from, going by viper's precedence
+func configSource(f *pflag.Flag) string {
+	switch {
+	case f.Changed:
+		return "flag"
+	case os.Getenv(strings.ToUpper(f.Name)) != "":
+		return "environment"
+	case viper.InConfig(f.Name):
+		return "config file"
+	}
+	return "default"
+}
@@ -0,0 +1,171 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// HandlerFunc answers a request to daedalus, or lets it through to the
+// next handler of its route if it is middleware
+type HandlerFunc func(*Context)
+
+// Context is a request to daedalus and the reply to it. It is the same
+// whichever router the request came in through, so the handlers don't
+// depend on any of them.
+type Context struct {
+	Writer  http.ResponseWriter
+	Request *http.Request
+	// the parts of the path matched by the :names of the route
+	param func(string) string
+	query url.Values
+	// what middleware found out about the request, like the client
+	keys map[string]string
+	// the handlers of the route and the one running
+	handlers []HandlerFunc
+	index    int
+}
+
+func newContext(w http.ResponseWriter, r *http.Request, param func(string) string) *Context {
+	return &Context{Writer: w, Request: r, param: param}
+}
+
+// Runs the handlers of the route one after the other
+func (c *Context) run(handlers []HandlerFunc) {
+	c.handlers, c.index = handlers, -1
+	c.Next()
+}
+
+// Runs the handlers after the current one.
+// Middleware calls it to let the request through.
+func (c *Context) Next() {
+	for c.index++; c.index < len(c.handlers); c.index++ {
+		c.handlers[c.index](c)
+	}
+}
+
+// Keeps the handlers after the current one from running
+func (c *Context) Abort() {
+	c.index = len(c.handlers)
+}
+
+// Returns the part of the path matched by :name
+func (c *Context) Param(name string) string {
+	return c.param(name)
+}
+
+// Returns the query parameter, or "" if it wasn't sent
+func (c *Context) Query(name string) string {
+	if c.query == nil {
+		c.query = c.Request.URL.Query()
+	}
+	return c.query.Get(name)
+}
+
+// Returns the request header
+func (c *Context) GetHeader(key string) string {
+	return c.Request.Header.Get(key)
+}
+
+// Sets the header of the reply, or removes it if value is empty
+func (c *Context) Header(key, value string) {
+	if value == "" {
+		c.Writer.Header().Del(key)
+		return
+	}
+	c.Writer.Header().Set(key, value)
+}
+
+// Remembers something about the request for the handlers after this one
+func (c *Context) Set(key, value string) {
+	if c.keys == nil {
+		c.keys = map[string]string{}
+	}
+	c.keys[key] = value
+}
+
+// Returns what an earlier handler remembered about the request
+func (c *Context) GetString(key string) string {
+	return c.keys[key]
+}
+
+// Replies with only a status
+func (c *Context) Status(code int) {
+	c.Writer.WriteHeader(code)
+}
+
+// Replies with data of the content type
+func (c *Context) Data(code int, contentType string, data []byte) {
+	c.Writer.Header().Set("Content-Type", contentType)
+	c.Writer.WriteHeader(code)
+	c.Writer.Write(data)
+}
+
+// Replies with formatted text
+func (c *Context) String(code int, format string, values ...interface{}) {
+	c.Writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	c.Writer.WriteHeader(code)
+	fmt.Fprintf(c.Writer, format, values...)
+}
+
+// Decodes the JSON body of the request into obj.
+// If it can't be decoded the request is answered with 400 and aborted.
+func (c *Context) BindJSON(obj interface{}) error {
+	if err := json.NewDecoder(c.Request.Body).Decode(obj); err != nil {
+		respond(c, http.StatusBadRequest, mazelib.Reply{Error: true, Message: "invalid request: " + err.Error()})
+		c.Abort()
+		return err
+	}
+	return nil
+}
+
+// Keeps calling step, flushing after each call, as long as it returns true
+// and the client is still there. Returns whether the client went away.
+func (c *Context) Stream(step func(w io.Writer) bool) bool {
+	flusher := http.NewResponseController(c.Writer)
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return true
+		default:
+			keepOpen := step(c.Writer)
+			flusher.Flush()
+			if !keepOpen {
+				return false
+			}
+		}
+	}
+}
+
+// Sends a server-sent event with the name, carrying the message as JSON
+func (c *Context) SSEvent(name string, message interface{}) {
+	h := c.Writer.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.Writer, "event:%s\ndata:%s\n\n", name, data)
+}
@@ -16,37 +16,58 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"time"
 
 	"bitbucket.org/mannih/gc6/mazelib"
-	"github.com/gin-gonic/gin"
+	"bitbucket.org/mannih/gc6/mazelib/gen"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// Returns the error code telling clients what went wrong
+func errorCode(err error) string {
+	if errors.Is(err, mazelib.ErrVictory) {
+		// the maze has been solved already
+		return mazelib.ErrCodeNoActiveMaze
+	}
+	return mazelib.ErrorCode(err)
+}
+
 type Maze struct {
-	rooms      [][]mazelib.Room
+	// the rooms by row, the one at x,y is at y*width+x
+	rooms      []mazelib.Room
+	width      int
 	start      mazelib.Coordinate
 	end        mazelib.Coordinate
 	icarus     mazelib.Coordinate
 	StepsTaken int
+	solved     bool
+	// the algorithm the maze was generated with, and the seed it was given
+	algorithm string
+	seed      int64
+	// the rooms Icarus walked into, in the order he did
+	path []mazelib.Coordinate
+	// set once the maze has been given up on and scored
+	retired bool
+	// when Icarus awoke in the maze, and whether he ran out of time since
+	started  time.Time
+	timedOut bool
+	// every move tried in the maze
+	trace []traceStep
+	// moves refused because of a wall or the edge of the maze
+	bumps int
 }
 
-// Tracking the current maze being solved
-
-// WARNING: This approach is not safe for concurrent use
-// This server is only intended to have a single client at a time
-// We would need a different and more complex approach if we wanted
-// concurrent connections than these simple package variables
-var currentMaze *Maze
-var scores []int
-
 // Defining the daedalus command.
 // This will be called as 'laybrinth daedalus'
 var daedalusCmd = &cobra.Command{
@@ -56,124 +77,521 @@ var daedalusCmd = &cobra.Command{
 	Long: `Daedalus's job is to create a challenging Labyrinth for his opponent
   Icarus to solve.
 
-  Daedalus runs a server which Icarus clients can connect to to solve laybrinths.`,
+  Daedalus runs a server which Icarus clients can connect to to solve laybrinths.
+  The routes are served with gin, or with net/http's ServeMux with
+  --router mux. Built with the nogin tag daedalus doesn't depend on gin
+  and always uses the ServeMux. With --grpc-addr he serves the same
+  protocol over gRPC as well, for icarus's --transport grpc.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		RunServer()
 	},
 }
 
 func init() {
-	rand.Seed(time.Now().UTC().UnixNano()) // need to initialize the seed
-	gin.SetMode(gin.ReleaseMode)
-
 	RootCmd.AddCommand(daedalusCmd)
 }
 
 // Runs the web server
+// Returns once the server has been shut down, either by ctrl+c or, if
+// exit-on-done is set, by Icarus calling /done.
 func RunServer() {
+	r, err := newRouter(viper.GetString("router"), !daedalusConf.Quiet)
+	if err != nil {
+		daedalusLog.Error("can't serve the laybrinths", "err", err)
+		return
+	}
+
+	s, err := openStore(viper.GetString("scores-file"))
+	if err != nil {
+		daedalusLog.Error("couldn't open the scores file", "err", err)
+		return
+	}
+	store = s
+
+	if err := checkServing(); err != nil {
+		daedalusLog.Error("can't serve the laybrinths", "err", err)
+		return
+	}
+	exitOnDone = viper.GetBool("exit-on-done")
+
+	l := serverListener
+	if l == nil {
+		if l, err = listen(); err != nil {
+			daedalusLog.Error("couldn't listen", "err", err)
+			return
+		}
+	}
+	daedalusLog.Info("listening", "addr", l.Addr().String())
+	rpc, err := serveGRPC(viper.GetString("grpc-addr"))
+	if err != nil {
+		l.Close()
+		daedalusLog.Error("couldn't serve gRPC", "err", err)
+		return
+	}
+	started = time.Now()
+	go games.expireIdle(viper.GetDuration("session-timeout"))
+	srv := &http.Server{Handler: r, Protocols: serverProtocols()}
+	// the event streams would keep the server from ever shutting down
+	srv.RegisterOnShutdown(events.close)
+	go func() {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			daedalusLog.Error("server failed", "err", err)
+			Shutdown()
+		}
+	}()
+
 	// Adding handling so that even when ctrl+c is pressed we still print
 	// out the results prior to exiting.
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
-	go func() {
-		<-c
-		printResults()
-		os.Exit(1)
-	}()
+	defer signal.Stop(c)
+	select {
+	case <-c:
+	case <-shutdown:
+	}
+
+	// give requests still in flight, like the reply to /done, time to finish
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		daedalusLog.Warn("couldn't shut down gracefully", "err", err)
+	}
+	stopGRPC(ctx, rpc)
+
+	games.retireAll()
+	printResults()
+	submitResults(games.scores(), onlyStrategy(store.since(started)))
+	notifications.Wait()
+	if path := viper.GetString("export"); path != "" {
+		if err := exportResults(path, store.since(started)); err != nil {
+			daedalusLog.Error("couldn't export the results", "err", err)
+		}
+	}
+}
+
+// Checks the settings daedalus serves laybrinths with make sense
+func checkServing() error {
+	if err := currentSettings().validate(); err != nil {
+		return fmt.Errorf("can't create laybrinths: %v", err)
+	}
+	if sb := viper.GetString("score-by"); sb != "steps" && sb != "time" {
+		return fmt.Errorf("unknown score-by %q, use steps or time", sb)
+	}
+	// every /awake without a session starts a game of its own
+	if viper.GetDuration("session-timeout") <= 0 {
+		return fmt.Errorf("session-timeout has to be more than 0, or the games of clients awaking without a session are never let go of")
+	}
+	return nil
+}
 
-	// Using gin-gonic/gin to handle our routing
-	r := gin.Default()
-	v1 := r.Group("/")
-	{
-		v1.GET("/awake", GetStartingPoint)
-		v1.GET("/move/:direction", MoveDirection)
-		v1.GET("/done", End)
+// Returns the routes of the server, served by whichever router newRouter makes
+func daedalusRoutes() []route {
+	var routes []route
+	r := &routeGroup{routes: &routes}
+	// The original API lives at the root, /v1 is the same for clients
+	// which want to be explicit about the version they speak.
+	for _, prefix := range []string{"/", "/v1"} {
+		v1 := r.Group(prefix, requireAPIKey)
+		{
+			v1.GET("/awake", GetStartingPoint)
+			v1.GET("/move/:direction", MoveDirection)
+			v1.GET("/batch/:directions", MoveDirections)
+			v1.GET("/ws", StreamMoves)
+			v1.GET("/done", End)
+			v1.GET("/scores", GetScores)
+			v1.GET("/stats", GetStats)
+			v1.GET("/events", StreamEvents)
+			v1.GET("/reveal", RevealMaze)
+			v1.GET("/trace", GetTrace)
+			v1.GET("/heatmap", GetHeatmap)
+		}
+	}
+	addV2Routes(r.Group("/v2", requireAPIKey))
+	addAdminRoutes(r.Group("/admin", requireAdminKey))
+	if viper.GetBool("pprof") {
+		r.Any("/debug/pprof/*profile", wrapH(pprofHandler()))
+	}
+	if viper.GetBool("ui") {
+		r.GET("/ui", ShowDashboard)
+		r.GET("/ui/maze", GetMazeView)
+		r.GET("/ui/maze.svg", GetMazeSVG)
+		r.GET("/ui/maze.png", GetMazePNG)
+		r.GET("/ui/events", FollowDashboard)
+	}
+	if viper.GetBool("play-page") {
+		r.GET("/play", ShowPlayPage)
 	}
+	return routes
+}
+
+// daedalus speaks HTTP/1 and, to clients which know he does, HTTP/2
+// without TLS on the same port
+func serverProtocols() *http.Protocols {
+	p := new(http.Protocols)
+	p.SetHTTP1(true)
+	p.SetUnencryptedHTTP2(true)
+	return p
+}
 
-	r.Run(":" + viper.GetString("port"))
+// Opens the socket daedalus listens on.
+// addr is either a host:port to bind to or unix:<path> for a Unix socket,
+// without it daedalus listens on port on all interfaces.
+func listen() (net.Listener, error) {
+	addr := viper.GetString("addr")
+	if addr == "" {
+		addr = ":" + viper.GetString("port")
+	}
+	if path := strings.TrimPrefix(addr, "unix:"); path != addr {
+		// a socket left behind by a daedalus that didn't shut down cleanly,
+		// but nothing else that happens to be there
+		if fi, err := os.Lstat(path); err == nil {
+			if fi.Mode()&os.ModeSocket == 0 {
+				return nil, fmt.Errorf("%s is there already and isn't a socket", path)
+			}
+			if err := os.Remove(path); err != nil {
+				return nil, err
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", addr)
 }
 
-// Ends a session and prints the results.
+// the listener RunServer serves on instead of opening one, if set
+var serverListener net.Listener
+
+// closed when the server should shut down
+var shutdown = make(chan struct{})
+
+// whether /done shuts the server down, which only RunServer sets so
+// routes mounted elsewhere never do
+var exitOnDone bool
+var shutdownOnce sync.Once
+
+// Asks a running server to shut down gracefully
+func Shutdown() {
+	shutdownOnce.Do(func() { close(shutdown) })
+}
+
+// Ends a session and replies with its results.
 // Called by Icarus when he has reached
 //   the number of times he wants to solve the laybrinth.
-func End(c *gin.Context) {
-	printResults()
-	os.Exit(1)
+// Shuts the server down afterwards if exit-on-done is set.
+func End(c *Context) {
+	g, ok := findGame(c)
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session"})
+		return
+	}
+
+	g.Lock()
+	results := g.finish()
+	g.notifyEnd("is done")
+	g.Unlock()
+
+	respond(c, http.StatusOK, results)
+	if exitOnDone {
+		Shutdown()
+	}
 }
 
 // initializes a new maze and places Icarus in his awakening location
-func GetStartingPoint(c *gin.Context) {
-	initializeMaze()
-	startRoom, err := currentMaze.Discover(currentMaze.Icarus())
+// Clients sending the id of their session get the maze within that
+// session, all others start a new one.
+func GetStartingPoint(c *Context) {
+	settings, err := requestedSettings(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, mazelib.Reply{Error: true, Message: err.Error()})
+		return
+	}
+
+	g, ok := findGame(c)
+	if c.Query("session") == "" || !ok {
+		g = games.create(c.GetString("client"))
+	}
+	g.Lock()
+	defer g.Unlock()
+	g.strategy = c.GetHeader("X-Strategy")
+
+	startRoom, err := g.startMaze(settings)
 	if err != nil {
-		fmt.Println("Icarus is outside of the maze. This shouldn't ever happen")
-		fmt.Println(err)
-		os.Exit(-1)
+		respond(c, http.StatusInternalServerError, mazelib.Reply{Error: true, Message: err.Error()})
+		return
 	}
-	mazelib.PrintMaze(currentMaze)
-	c.JSON(http.StatusOK, mazelib.Reply{Survey: startRoom})
+	respond(c, http.StatusOK, mazelib.Reply{Survey: startRoom, Session: g.id, MazeID: g.mazeID})
+}
+
+// Looks up the game a request belongs to and locks it.
+// Replies with an error and returns false if there is no such game.
+func lockGame(c *Context) (*game, bool) {
+	g, ok := findGame(c)
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session, call /awake first", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return nil, false
+	}
+	g.Lock()
+	if g.maze == nil {
+		g.Unlock()
+		respond(c, 409, mazelib.Reply{Error: true, Message: mazelib.ErrNoMaze.Error(), ErrorCode: mazelib.ErrorCode(mazelib.ErrNoMaze)})
+		return nil, false
+	}
+	return g, true
 }
 
 // The API response to the /move/:direction address
-func MoveDirection(c *gin.Context) {
-	var err error
+func MoveDirection(c *Context) {
+	g, ok := lockGame(c)
+	if !ok {
+		return
+	}
+	defer g.Unlock()
+
+	r := g.move(c.Param("direction"))
+	respond(c, statusFor(r), r)
+}
 
-	switch c.Param("direction") {
-	case "left":
-		err = currentMaze.MoveLeft()
-	case "right":
-		err = currentMaze.MoveRight()
-	case "down":
-		err = currentMaze.MoveDown()
-	case "up":
-		err = currentMaze.MoveUp()
+// Returns the HTTP status a reply is sent with
+func statusFor(r mazelib.Reply) int {
+	switch {
+	case !r.Error:
+		return http.StatusOK
+	case r.ErrorCode == mazelib.ErrCodeInvalidDirection:
+		return http.StatusBadRequest
 	}
+	return 409
+}
+
+// The API response to the /batch/:directions address.
+// Takes a comma separated list of directions and walks them one after the
+// other, replying with a list of the replies to each step.
+// Stops at the first step that fails or reaches the treasure.
+func MoveDirections(c *Context) {
+	g, ok := lockGame(c)
+	if !ok {
+		return
+	}
+	defer g.Unlock()
+
+	respond(c, http.StatusOK, g.moveAll(strings.Split(c.Param("directions"), ",")))
+}
 
+// Moves Icarus along the given directions, stopping at the first step that
+// fails or reaches the treasure. Returns the replies to every step taken.
+func (g *game) moveAll(directions []string) []mazelib.Reply {
+	var replies []mazelib.Reply
+	for _, d := range directions {
+		r := g.move(d)
+		replies = append(replies, r)
+		if r.Error || r.Victory {
+			break
+		}
+	}
+	return replies
+}
+
+// Moves Icarus one step and surveys the room he ends up in.
+// The move is traced, whether daedalus lets Icarus make it or not.
+func (g *game) move(direction string) mazelib.Reply {
+	g.lastActive = time.Now()
+	r := g.step(direction)
+	g.trace(direction, r)
+	r.MazeID = g.mazeID
+	r.StepsTaken = g.maze.StepsTaken
+	r.MoveNumber = len(g.maze.trace)
+	return r
+}
+
+func (g *game) step(direction string) mazelib.Reply {
+	var err error
 	var r mazelib.Reply
 
+	if g.maze.solved {
+		r.Error = true
+		r.Message = "maze is solved already, call /awake for a new one"
+		r.ErrorCode = mazelib.ErrCodeNoActiveMaze
+		return r
+	}
+	if limit := daedalusConf.TimeLimit; limit > 0 && time.Since(g.maze.started) > limit {
+		g.timeOut()
+	}
+	if g.maze.retired {
+		r.Error = true
+		r.Message = "Icarus took too many steps in this maze, call /awake for a new one"
+		r.ErrorCode = mazelib.ErrCodeStepLimit
+		if g.maze.timedOut {
+			r.Message = "Icarus took too long in this maze, call /awake for a new one"
+			r.ErrorCode = mazelib.ErrCodeTimeLimit
+		}
+		return r
+	}
+
+	d, err := mazelib.ParseDirection(direction)
 	if err != nil {
 		r.Error = true
 		r.Message = err.Error()
-		c.JSON(409, r)
-		return
+		r.ErrorCode = mazelib.ErrCodeInvalidDirection
+		return r
 	}
+	err = g.maze.move(d)
 
-	s, e := currentMaze.LookAround()
+	if err != nil {
+		if errors.Is(err, mazelib.ErrWall) {
+			g.publish(eventWall, direction)
+		}
+		if errors.Is(err, mazelib.ErrWall) || errors.Is(err, mazelib.ErrOutOfBounds) {
+			g.maze.bumps++
+		}
+		daedalusLog.Debug("refused move", "session", g.id, "maze", g.mazeID, "direction", direction, "err", err)
+		r.Error = true
+		r.Message = err.Error()
+		r.ErrorCode = errorCode(err)
+		return r
+	}
+	g.maze.path = append(g.maze.path, g.maze.icarus)
+	g.publish(eventMove, direction)
+
+	s, e := g.maze.LookAround()
 
 	if e != nil {
-		if e == mazelib.ErrVictory {
-			scores = append(scores, currentMaze.StepsTaken)
+		if errors.Is(e, mazelib.ErrVictory) {
+			g.maze.solved = true
+			g.notifyRecord()
+			// bumping into walls may cost steps as well
+			g.record(g.maze.StepsTaken + daedalusConf.WallPenalty*g.maze.bumps)
+			g.publish(eventVictory, direction)
+			daedalusLog.Info("victory", "session", g.id, "maze", g.mazeID, "steps", g.maze.StepsTaken)
+			if daedalusConf.Color {
+				g.show(append([]mazelib.Coordinate{g.maze.start}, g.maze.path...))
+			}
 			r.Victory = true
-			r.Message = fmt.Sprintf("Victory achieved in %d steps \n", currentMaze.StepsTaken)
+			r.WallBumps = g.maze.bumps
+			r.Message = fmt.Sprintf("Victory achieved in %d steps \n", g.maze.StepsTaken)
 		} else {
 			r.Error = true
-			r.Message = err.Error()
+			r.Message = e.Error()
+			r.ErrorCode = errorCode(e)
 		}
+	} else if limit := daedalusConf.StepLimit; limit > 0 && g.maze.StepsTaken >= limit {
+		g.retireMaze()
+		r.Error = true
+		r.Message = fmt.Sprintf("Icarus took the %d steps a maze allows without finding the treasure, call /awake for a new one", limit)
+		r.ErrorCode = mazelib.ErrCodeStepLimit
 	}
 	r.Survey = s
-	c.JSON(http.StatusOK, r)
-}
-
-func initializeMaze() {
-	currentMaze = createMaze()
+	return r
 }
 
 // Print to the terminal the average steps to solution for the current session
 func printResults() {
+	results := store.since(started)
+	var durations []time.Duration
+	for _, r := range results {
+		durations = append(durations, r.Duration)
+	}
+	if jsonOutput() {
+		printJSON(report(games.scores(), durations, results))
+		return
+	}
+	printSummary(games.scores(), durations)
+	printAlgorithms(byAlgorithm(results))
+
+	if len(viper.GetStringSlice("api-keys")) > 0 {
+		for client, s := range games.scoresByClient() {
+			fmt.Printf("  %s solved %d times with an avg of %d steps\n", client, len(s), mazelib.AvgScores(s))
+			printScore("  ", scoring().Score(s))
+		}
+	}
+}
+
+// Sums up the scores of the server, and of every client if there are api keys
+func report(scores []int, durations []time.Duration, results []result) daedalusReport {
+	r := daedalusReport{summary: summarize(scores, durations), Algorithms: byAlgorithm(results)}
+	if len(viper.GetStringSlice("api-keys")) > 0 {
+		r.Clients = map[string]summary{}
+		for client, s := range games.scoresByClient() {
+			r.Clients[client] = summarize(s, nil)
+		}
+	}
+	return r
+}
+
+// Prints the average steps, the score and how the steps are distributed.
+// Scoring by time adds how long the mazes took.
+func printSummary(scores []int, durations []time.Duration) {
 	fmt.Printf("Labyrinth solved %d times with an avg of %d steps\n", len(scores), mazelib.AvgScores(scores))
+	printScore("", scoring().Score(scores))
+	printDistribution(mazelib.Statistics(scores, histogramBuckets))
+	if scoringByTime() && len(durations) > 0 {
+		printTimes(durations)
+	}
+}
+
+// Prints how each algorithm did, if there was more than one
+func printAlgorithms(as []algorithmScore) {
+	if len(as) < 2 {
+		return
+	}
+	fmt.Println("By algorithm:")
+	for _, a := range as {
+		fmt.Printf("  %s: %d mazes, %d solved, avg of %d steps, score %d\n", a.Algorithm, a.Mazes, a.Solved, a.AverageSteps, a.Score)
+	}
+}
+
+// Prints how long the mazes took, on average and how that is distributed
+func printTimes(durations []time.Duration) {
+	var ms []int
+	for _, d := range durations {
+		ms = append(ms, int(d/time.Millisecond))
+	}
+	st := mazelib.Statistics(ms, histogramBuckets)
+	fmt.Printf("Time per maze: avg %dms, median %dms, p90 %dms, p99 %dms\n", st.Mean, st.Median, st.P90, st.P99)
+}
+
+func printScore(indent string, s mazelib.Score) {
+	fmt.Printf("%sScore: %d (%d of %d mazes solved, %d counted as %d steps)\n", indent, s.Final, s.Solved, s.Mazes, s.Penalized, viper.GetInt("max-steps"))
+}
+
+// the number of buckets of the histograms of steps
+const histogramBuckets = 10
+
+func printDistribution(st mazelib.Stats) {
+	if st.Count == 0 {
+		return
+	}
+	fmt.Printf("Median %d, p90 %d, p99 %d, standard deviation %.1f steps\n", st.Median, st.P90, st.P99, st.StdDev)
+	for _, b := range st.Histogram {
+		fmt.Printf("  %4d-%-4d %4d %s\n", b.From, b.To, b.Count, strings.Repeat("#", b.Count*40/st.Count))
+	}
+}
+
+// Whether the time mazes take is scored alongside the steps
+func scoringByTime() bool {
+	return viper.GetString("score-by") == "time"
+}
+
+// Returns the rules games are scored by
+func scoring() mazelib.Scoring {
+	return mazelib.Scoring{Mazes: viper.GetInt("evaluation-mazes"), MaxSteps: viper.GetInt("max-steps")}
 }
 
 // Return a room from the maze
 func (m *Maze) GetRoom(x, y int) (*mazelib.Room, error) {
-	if x < 0 || y < 0 || x >= m.Width() || y >= m.Height() {
-		return &mazelib.Room{}, errors.New("room outside of maze boundaries")
+	if !(mazelib.Coordinate{X: x, Y: y}).In(m.Width(), m.Height()) {
+		return &mazelib.Room{}, mazelib.ErrOutOfBounds
 	}
 
-	return &m.rooms[y][x], nil
+	return &m.rooms[y*m.width+x], nil
 }
 
-func (m *Maze) Width() int  { return len(m.rooms[0]) }
-func (m *Maze) Height() int { return len(m.rooms) }
+func (m *Maze) Width() int { return m.width }
+func (m *Maze) Height() int {
+	if m.width == 0 {
+		return 0
+	}
+	return len(m.rooms) / m.width
+}
 
 // Return Icarus's current position
 func (m *Maze) Icarus() (x, y int) {
@@ -193,6 +611,7 @@ func (m *Maze) SetStartPoint(x, y int) error {
 	}
 
 	r.Start = true
+	m.start = mazelib.Coordinate{x, y}
 	m.icarus = mazelib.Coordinate{x, y}
 	return nil
 }
@@ -218,7 +637,6 @@ func (m *Maze) SetTreasure(x, y int) error {
 // Will return ErrVictory if Icarus is at the treasure.
 func (m *Maze) LookAround() (mazelib.Survey, error) {
 	if m.end.X == m.icarus.X && m.end.Y == m.icarus.Y {
-		fmt.Printf("Victory achieved in %d steps \n", m.StepsTaken)
 		return mazelib.Survey{}, mazelib.ErrVictory
 	}
 
@@ -226,306 +644,146 @@ func (m *Maze) LookAround() (mazelib.Survey, error) {
 }
 
 // Given two points, survey the room.
-// Will return error if two points are outside of the maze
+// Will return ErrOutOfBounds if the two points are outside of the maze
 func (m *Maze) Discover(x, y int) (mazelib.Survey, error) {
-	if r, err := m.GetRoom(x, y); err != nil {
-		return mazelib.Survey{}, nil
-	} else {
-		return r.Walls, nil
+	r, err := m.GetRoom(x, y)
+	if err != nil {
+		return mazelib.Survey{}, err
 	}
+	return r.Walls, nil
 }
 
 // Moves Icarus's position left one step
 // Will not permit moving through walls or out of the maze
-func (m *Maze) MoveLeft() error {
-	s, e := m.LookAround()
-	if e != nil {
-		return e
-	}
-	if s.Left {
-		return errors.New("Can't walk through walls")
-	}
-
-	x, y := m.Icarus()
-	if _, err := m.GetRoom(x-1, y); err != nil {
-		return err
-	}
-
-	m.icarus = mazelib.Coordinate{x - 1, y}
-	m.StepsTaken++
-	return nil
-}
+func (m *Maze) MoveLeft() error { return m.move(mazelib.W) }
 
 // Moves Icarus's position right one step
-// Will not permit moving through walls or out of the maze
-func (m *Maze) MoveRight() error {
-	s, e := m.LookAround()
-	if e != nil {
-		return e
-	}
-	if s.Right {
-		return errors.New("Can't walk through walls")
-	}
+func (m *Maze) MoveRight() error { return m.move(mazelib.E) }
 
-	x, y := m.Icarus()
-	if _, err := m.GetRoom(x+1, y); err != nil {
-		return err
-	}
+// Moves Icarus's position up one step
+func (m *Maze) MoveUp() error { return m.move(mazelib.N) }
 
-	m.icarus = mazelib.Coordinate{x + 1, y}
-	m.StepsTaken++
-	return nil
-}
+// Moves Icarus's position down one step
+func (m *Maze) MoveDown() error { return m.move(mazelib.S) }
 
-// Moves Icarus's position up one step
+// Moves Icarus's position one step in the direction
 // Will not permit moving through walls or out of the maze
-func (m *Maze) MoveUp() error {
+func (m *Maze) move(d mazelib.Direction) error {
 	s, e := m.LookAround()
 	if e != nil {
 		return e
 	}
-	if s.Top {
-		return errors.New("Can't walk through walls")
+	if !s.HasExit(d) {
+		return mazelib.ErrWall
 	}
 
-	x, y := m.Icarus()
-	if _, err := m.GetRoom(x, y-1); err != nil {
-		return err
+	n, ok := mazelib.GridOf(m).Neighbor(m.icarus, d)
+	if !ok {
+		return mazelib.ErrOutOfBounds
 	}
 
-	m.icarus = mazelib.Coordinate{x, y - 1}
+	m.icarus = n
 	m.StepsTaken++
 	return nil
 }
 
-// Moves Icarus's position down one step
-// Will not permit moving through walls or out of the maze
-func (m *Maze) MoveDown() error {
-	s, e := m.LookAround()
-	if e != nil {
-		return e
-	}
-	if s.Bottom {
-		return errors.New("Can't walk through walls")
-	}
+// NewEmptyMaze creates a maze without any walls, or start and treasure yet
+// Good starting point for additive algorithms
+func NewEmptyMaze(xSize, ySize int) *Maze {
+	z := mazePool.Get().(*Maze)
 
-	x, y := m.Icarus()
-	if _, err := m.GetRoom(x, y+1); err != nil {
-		return err
+	if n := xSize * ySize; cap(z.rooms) < n {
+		z.rooms = make([]mazelib.Room, n)
+	} else {
+		z.rooms = z.rooms[:n]
+		for i := range z.rooms {
+			z.rooms[i] = mazelib.Room{}
+		}
 	}
+	z.width = xSize
 
-	m.icarus = mazelib.Coordinate{x, y + 1}
-	m.StepsTaken++
-	return nil
+	return z
 }
 
-// Creates a maze without any walls
-// Good starting point for additive algorithms
-func emptyMaze() *Maze {
-	z := Maze{}
-	ySize := viper.GetInt("height")
-	xSize := viper.GetInt("width")
-
-	z.rooms = make([][]mazelib.Room, ySize)
-	for y := 0; y < ySize; y++ {
-		z.rooms[y] = make([]mazelib.Room, xSize)
-		for x := 0; x < xSize; x++ {
-			z.rooms[y][x] = mazelib.Room{}
-		}
-	}
+// Mazes which are let go of with release end up here, to have their rooms
+// reused by NewEmptyMaze. Saves allocating them anew for every one of the
+// mazes bench generates and throws away.
+var mazePool = sync.Pool{New: func() interface{} { return new(Maze) }}
 
-	return &z
+// Hands the maze back to NewEmptyMaze, it mustn't be used afterwards
+func (m *Maze) release() {
+	*m = Maze{rooms: m.rooms[:0]}
+	mazePool.Put(m)
 }
 
-// Creates a maze with all walls
+// NewFullMaze creates a maze with all walls, but no start and treasure yet
 // Good starting point for subtractive algorithms
-func fullMaze() *Maze {
-	z := emptyMaze()
-	ySize := viper.GetInt("height")
-	xSize := viper.GetInt("width")
-
-	for y := 0; y < ySize; y++ {
-		for x := 0; x < xSize; x++ {
-			z.rooms[y][x].Walls = mazelib.Survey{true, true, true, true}
+func NewFullMaze(xSize, ySize int) *Maze {
+	return mazeOf(gen.Full(xSize, ySize))
+}
+
+// Creates a maze of the walls of every room, by row
+func mazeOf(walls [][]mazelib.Survey) *Maze {
+	z := NewEmptyMaze(len(walls[0]), len(walls))
+	for y, row := range walls {
+		for x, s := range row {
+			z.rooms[y*z.width+x].Walls = s
 		}
 	}
-
 	return z
 }
 
 // TODO: Write your maze creator function here
-func createMaze() *Maze {
+func createMaze(s mazeSettings) *Maze {
 	// TODO: Fill in the maze:
 	// You need to insert a startingPoint for Icarus
 	// You need to insert an EndingPoint (treasure) for Icarus
 	// You need to Add and Remove walls as needed.
 	// Use the mazelib.AddWall & mazelib.RmWall to do this
-	rand.Seed(time.Now().UTC().UnixNano())
-	var m *Maze
-	r := rand.Intn(5)
-	switch r {
-	case 0, 1, 2:
-		m = createBinaryTreeWithHoles()
-	case 3:
-		m = createBinaryTree()
-	case 4:
-		m = createGrowingTree()
+	// the same seed makes the same maze
+	seed := s.Seed
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	algorithm := s.Algorithm
+	if algorithm == "random" {
+		switch rng.Intn(5) {
+		case 0, 1, 2:
+			algorithm = "binarytree-holes"
+		case 3:
+			algorithm = "binarytree"
+		case 4:
+			algorithm = "growingtree"
+		}
+	}
+	m := mazeOf(gen.Parallel(gen.Generators[algorithm], daedalusConf.ParallelGeneration)(rng, s.Width, s.Height))
+	m.algorithm = algorithm
+	m.seed = seed
+
+	if s.Difficulty == "extreme" {
+		p := mazelib.LongestPath(m)
+		m.SetTreasure(p[len(p)-1].X, p[len(p)-1].Y)
+		m.SetStartPoint(p[0].X, p[0].Y)
+		return m
 	}
+
 	//Insert Treasure
-	xt := rand.Intn(viper.GetInt("width") - 1)
-	yt := rand.Intn(viper.GetInt("height") - 1)
+	xt := rng.Intn(s.Width - 1)
+	yt := rng.Intn(s.Height - 1)
 	m.SetTreasure(xt, yt)
-	rand.Seed(time.Now().UTC().UnixNano())
 	//Insert starting point
 
-	xs := rand.Intn(viper.GetInt("width") - 1)
-	ys := rand.Intn(viper.GetInt("height") - 1)
-	//make sure, starting point is away from treasure
-	for xs+ys == xt+yt {
-		xs = rand.Intn(viper.GetInt("width") - 1)
-		ys = rand.Intn(viper.GetInt("height") - 1)
+	xs := rng.Intn(s.Width - 1)
+	ys := rng.Intn(s.Height - 1)
+	//make sure, starting point is away from treasure, as far as the difficulty asks for
+	for !s.startFits(mazelib.Coordinate{X: xs, Y: ys}, mazelib.Coordinate{X: xt, Y: yt}) {
+		xs = rng.Intn(s.Width - 1)
+		ys = rng.Intn(s.Height - 1)
 	}
 	m.SetStartPoint(xs, ys)
 
 	return m
 
 }
-
-// based on the binary tree algorithm
-func createBinaryTree() *Maze {
-	// we can either make a connection to the room below or right from the current one
-	m := fullMaze()
-	for y := 0; y < m.Height(); y++ {
-		for x := 0; x < m.Width(); x++ {
-
-			dir := rand.Intn(2)
-			// if we are at the right boarder, we can only go down
-			if (y == m.Height()-1) && (x == m.Width()-1) {
-				break
-			} else if x == m.Width()-1 {
-				dir = 1
-			} else if y == m.Height()-1 {
-				dir = 0
-			}
-			switch dir {
-			case 0:
-				m.rooms[y][x].RmWall(mazelib.E)
-				m.rooms[y][x+1].RmWall(mazelib.W)
-			case 1:
-				m.rooms[y][x].RmWall(mazelib.S)
-				m.rooms[y+1][x].RmWall(mazelib.N)
-			}
-		}
-	}
-	return m
-}
-
-// its based on the binary Tree algorithm, but sometimes, we add additional holes in the wall to create some loops
-func createBinaryTreeWithHoles() *Maze {
-	// we can either make a connection to the room below or right from the current one
-	m := fullMaze()
-	for y := 0; y < m.Height(); y++ {
-		for x := 0; x < m.Width(); x++ {
-
-			dir := rand.Intn(2)
-			// if we are at the right boarder, we can only go down
-			if (y == m.Height()-1) && (x == m.Width()-1) {
-				break
-			} else if x == m.Width()-1 {
-				dir = 1
-			} else if y == m.Height()-1 {
-				dir = 0
-			}
-			switch dir {
-			case 0:
-				m.rooms[y][x].RmWall(mazelib.E)
-				m.rooms[y][x+1].RmWall(mazelib.W)
-			case 1:
-				m.rooms[y][x].RmWall(mazelib.S)
-				m.rooms[y+1][x].RmWall(mazelib.N)
-			case 2:
-				m.rooms[y][x].RmWall(mazelib.E)
-				m.rooms[y][x+1].RmWall(mazelib.W)
-				m.rooms[y][x].RmWall(mazelib.S)
-				m.rooms[y+1][x].RmWall(mazelib.N)
-			}
-		}
-	}
-	return m
-}
-
-//growing tree algorithm
-func createGrowingTree() *Maze {
-
-	// starting with a full maze
-	m := fullMaze()
-	// create an 2D array for visited cells
-	visited := make([][]bool, m.Width())
-	for i := 0; i < m.Width(); i++ {
-		visited[i] = make([]bool, m.Height())
-	}
-	// create an array for active cells
-	cells := make([]mazelib.Coordinate, 1)
-	//select a random starting point for the creation
-	y := rand.Intn(m.Height() - 1)
-	x := rand.Intn(m.Width() - 1)
-	cells[0] = mazelib.Coordinate{x, y}
-	visited[x][y] = true
-	for len(cells) > 0 {
-		//we use the newest cell to work with
-		active := cells[len(cells)-1]
-		//lets see if it has unvisited neighbors
-		x, y = active.X, active.Y
-		if (x == 0 || visited[x-1][y]) && (x == m.Width()-1 || visited[x+1][y]) && (y == 0 || visited[x][y-1]) && (y == m.Height()-1 || visited[x][y+1]) {
-			cells = cells[:len(cells)-1]
-		}
-		//shuffle directions (up, down, left, right)
-		dirs := rand.Perm(4)
-		for _, d := range dirs {
-			if !active.IsNil() {
-				switch d {
-				case 0: //up
-					if (y > 0) && !(visited[x][y-1]) {
-						//carve up, add that to cells, and visited, set active nil,
-						active.X = -1
-						cells = append(cells, mazelib.Coordinate{x, y - 1})
-						m.rooms[y-1][x].RmWall(mazelib.S)
-						m.rooms[y][x].RmWall(mazelib.N)
-						visited[x][y-1] = true
-					}
-				case 1: //down
-					if (y < m.Height()-1) && !(visited[x][y+1]) {
-						//carve up, add that to cells, set active nil
-						active.X = -1
-						cells = append(cells, mazelib.Coordinate{x, y + 1})
-						m.rooms[y][x].RmWall(mazelib.S)
-						m.rooms[y+1][x].RmWall(mazelib.N)
-						visited[x][y+1] = true
-					}
-
-				case 2: //left
-					if (x > 0) && !(visited[x-1][y]) {
-						//carve up, add that to cells, set active nil
-						active.X = -1
-						cells = append(cells, mazelib.Coordinate{x - 1, y})
-						m.rooms[y][x].RmWall(mazelib.W)
-						m.rooms[y][x-1].RmWall(mazelib.E)
-						visited[x-1][y] = true
-					}
-
-				case 3: //right
-					if (x < m.Width()-1) && !(visited[x+1][y]) {
-						//carve up, add that to cells, set active nil
-						active.X = -1
-						cells = append(cells, mazelib.Coordinate{x + 1, y})
-						m.rooms[y][x].RmWall(mazelib.E)
-						m.rooms[y][x+1].RmWall(mazelib.W)
-						visited[x+1][y] = true
-					}
-				}
-			}
-		}
-	}
-
-	return m
-}
@@ -0,0 +1,77 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the duel command.
+// This will be called as 'laybrinth duel'
+var duelCmd = &cobra.Command{
+	Use:   "duel",
+	Short: "Let Icarus solve Daedalus's laybrinths in this process",
+	Long: `In a duel Daedalus and Icarus play against each other in a single
+  process, without a server in between, and the results of both are printed
+  when Icarus has solved the laybrinth the times asked for.
+
+  It's the quickest way to see how a change to either of them plays out.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RunDuel()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(duelCmd)
+}
+
+func RunDuel() {
+	settings := currentSettings()
+	if err := settings.validate(); err != nil {
+		daedalusLog.Error("can't create mazes", "err", err)
+		return
+	}
+	if _, err := newStrategy(viper.GetString("strategy"), nil); err != nil {
+		icarusLog.Error("unknown strategy", "err", err)
+		return
+	}
+
+	begun := time.Now()
+	l := newLocalGame("duel", viper.GetString("strategy"), settings)
+	var stats []solveStats
+	for x := 0; x < viper.GetInt("times"); x++ {
+		strat, _ := newStrategy(viper.GetString("strategy"), nil)
+		s, _ := solveMaze(l, strat, settings.Width*settings.Height)
+		stats = append(stats, s)
+	}
+	l.finish()
+	saveLearned()
+
+	if jsonOutput() {
+		printJSON(duelReport{
+			Icarus:   icarusReport{Mazes: stats},
+			Daedalus: daedalusReport{summary: summarize(l.game.scores, l.game.durations), Algorithms: byAlgorithm(store.since(begun))},
+		})
+	} else {
+		printSolveStats(stats)
+		printSummary(l.game.scores, l.game.durations)
+		printAlgorithms(byAlgorithm(store.since(begun)))
+	}
+	submitResults(l.game.scores, viper.GetString("strategy"))
+}
@@ -0,0 +1,108 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"math"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// Every maze played is a match between the algorithm that generated it and
+// the strategy Icarus solved it with. The ratings of both follow the Elo
+// system: the strategy scores 1 for a maze solved without a step, 0 for one
+// that took max-steps or wasn't solved at all, and in between the fewer steps
+// it took. Beating a highly rated opponent gains more than beating a weak one.
+
+const (
+	// the rating everyone starts out with
+	initialRating = 1500
+	// how much a single match can change a rating
+	eloK = 16
+)
+
+// rating is the Elo rating of an algorithm or a strategy
+type rating struct {
+	Name   string `json:"name"`
+	Rating int    `json:"rating"`
+	Mazes  int    `json:"mazes"`
+}
+
+// ratings of the algorithms and strategies, best first
+type ratings struct {
+	Algorithms []rating `json:"algorithms"`
+	Strategies []rating `json:"strategies"`
+}
+
+// player is an algorithm or strategy being rated
+type player struct {
+	rating float64
+	mazes  int
+}
+
+// Rates the algorithms and strategies by the results, in the order they were played.
+// Results of clients that didn't tell their strategy are left out.
+func rate(results []result) ratings {
+	algorithms := map[string]*player{}
+	strategies := map[string]*player{}
+	maxSteps := float64(viper.GetInt("max-steps"))
+
+	for _, r := range results {
+		if r.Strategy == "" || r.Algorithm == "" {
+			continue
+		}
+		a := playerIn(algorithms, r.Algorithm)
+		s := playerIn(strategies, r.Strategy)
+
+		score := 0.0
+		if r.Solved && maxSteps > 0 {
+			score = math.Max(0, 1-float64(r.Steps)/maxSteps)
+		}
+		expected := 1 / (1 + math.Pow(10, (a.rating-s.rating)/400))
+		s.rating += eloK * (score - expected)
+		a.rating -= eloK * (score - expected)
+		a.mazes++
+		s.mazes++
+	}
+
+	return ratings{Algorithms: ranked(algorithms), Strategies: ranked(strategies)}
+}
+
+// Returns the player of the name, starting out with the initial rating if he's new
+func playerIn(players map[string]*player, name string) *player {
+	p, ok := players[name]
+	if !ok {
+		p = &player{rating: initialRating}
+		players[name] = p
+	}
+	return p
+}
+
+// Turns the players into a list of ratings, best first
+func ranked(players map[string]*player) []rating {
+	rs := []rating{}
+	for name, p := range players {
+		rs = append(rs, rating{Name: name, Rating: int(math.Floor(p.rating + 0.5)), Mazes: p.mazes})
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Rating != rs[j].Rating {
+			return rs[i].Rating > rs[j].Rating
+		}
+		return rs[i].Name < rs[j].Name
+	})
+	return rs
+}
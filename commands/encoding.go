@@ -0,0 +1,174 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+)
+
+// Besides JSON daedalus replies in MessagePack or CBOR to clients asking
+// for it in their Accept header, which is cheaper to encode and decode.
+// The field names are the same in all of them. Websockets and the event
+// stream always speak JSON.
+
+const (
+	mimeJSON    = "application/json"
+	mimeMsgpack = "application/msgpack"
+	mimeCBOR    = "application/cbor"
+)
+
+var (
+	msgpackHandle = &codec.MsgpackHandle{RawToString: true}
+	cborHandle    = &codec.CborHandle{}
+)
+
+// Returns the codec for the media type, nil for JSON
+func codecFor(mime string) codec.Handle {
+	switch mime {
+	case mimeMsgpack:
+		return msgpackHandle
+	case mimeCBOR:
+		return cborHandle
+	}
+	return nil
+}
+
+// Picks the media type to reply with from an Accept header.
+// Clients asking for none of the binary ones get JSON.
+func negotiate(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mime := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		switch mime {
+		case mimeMsgpack, "application/x-msgpack":
+			return mimeMsgpack
+		case mimeCBOR:
+			return mimeCBOR
+		}
+	}
+	return mimeJSON
+}
+
+// replyBuffer is a buffer replies are encoded into, with the JSON encoder
+// writing to it
+type replyBuffer struct {
+	bytes.Buffer
+	json *json.Encoder
+}
+
+// Replies are encoded, and read by icarus, into buffers kept here in between,
+// so a solve doesn't allocate a new one for every move
+var replyBuffers = sync.Pool{New: func() interface{} {
+	b := &replyBuffer{}
+	b.json = json.NewEncoder(&b.Buffer)
+	return b
+}}
+
+// the media type JSON replies are sent as
+const jsonContentType = mimeJSON + "; charset=utf-8"
+
+// Replies with obj, encoded the way the client asked for
+func respond(c *Context, code int, obj interface{}) {
+	mime := negotiate(c.GetHeader("Accept"))
+	b := replyBuffers.Get().(*replyBuffer)
+	defer replyBuffers.Put(b)
+
+	if err := b.encode(mime, obj); err != nil {
+		c.String(http.StatusInternalServerError, "%v", err)
+		return
+	}
+	if mime == mimeJSON {
+		mime = jsonContentType
+	}
+	c.Data(code, mime, b.Bytes())
+}
+
+// Encodes obj in the encoding of the media type, replacing what the
+// buffer held before
+func (b *replyBuffer) encode(mime string, obj interface{}) error {
+	b.Reset()
+	if h := codecFor(mime); h != nil {
+		return codec.NewEncoder(b, h).Encode(obj)
+	}
+	return b.json.Encode(obj)
+}
+
+// Returns the media type icarus asks daedalus to reply with
+func encoding() string {
+	switch icarusConf.Encoding {
+	case "msgpack":
+		return mimeMsgpack
+	case "cbor":
+		return mimeCBOR
+	}
+	return mimeJSON
+}
+
+// Decodes the reply of daedalus right off the response, in the encoding he
+// replied in, into v. Without a v the reply is thrown away. Either way the
+// body is read to the end so the connection can be reused.
+func decodeResponse(response *http.Response, v interface{}) error {
+	var err error
+	if v != nil {
+		mime := response.Header.Get("Content-Type")
+		if i := strings.IndexByte(mime, ';'); i >= 0 {
+			mime = mime[:i]
+		}
+		if h := codecFor(strings.TrimSpace(mime)); h != nil {
+			err = codec.NewDecoder(response.Body, h).Decode(v)
+		} else {
+			err = decodeJSON(response.Body, v)
+		}
+	}
+	io.Copy(ioutil.Discard, response.Body)
+	return err
+}
+
+// Decodes the JSON reply read from body into v.
+// It's read into one of the buffers replies are encoded into first, a
+// json.Decoder reading right off the body would allocate a buffer of its own
+// for every reply.
+func decodeJSON(body io.Reader, v interface{}) error {
+	b := replyBuffers.Get().(*replyBuffer)
+	defer replyBuffers.Put(b)
+	b.Reset()
+	if _, err := b.ReadFrom(body); err != nil {
+		return err
+	}
+	return json.Unmarshal(b.Bytes(), v)
+}
+
+// Decodes a reply of daedalus, encoded the way icarus asked for.
+// Falls back to JSON for servers which don't speak the encoding.
+func decode(in []byte, v interface{}) error {
+	h := codecFor(encoding())
+	if h == nil {
+		return json.Unmarshal(in, v)
+	}
+	if err := codec.NewDecoderBytes(in, h).Decode(v); err != nil {
+		if json.Unmarshal(in, v) != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// a reply like the ones to most moves
+var benchReply = mazelib.Reply{Survey: mazelib.Survey{Top: true, Left: true}, MazeID: 3, StepsTaken: 1234, MoveNumber: 1300}
+
+// the encodings replies are sent in, by the names the benchmarks go by
+var benchEncodings = []struct{ name, mime string }{
+	{"json", mimeJSON},
+	{"msgpack", mimeMsgpack},
+	{"cbor", mimeCBOR},
+}
+
+// Encodes benchReply the way daedalus replies to a move, and the way JSON
+// replies were encoded before they reused their buffers, into a new one
+// every time
+func BenchmarkReplyEncode(b *testing.B) {
+	b.Run("json-unbuffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(benchReply); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, e := range benchEncodings {
+		b.Run(e.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf := replyBuffers.Get().(*replyBuffer)
+				err := buf.encode(e.mime, benchReply)
+				replyBuffers.Put(buf)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Decodes benchReply the way icarus reads the reply to a move, and the way
+// JSON replies were read before, into a new buffer every time
+func BenchmarkReplyDecode(b *testing.B) {
+	b.Run("json-unbuffered", func(b *testing.B) {
+		data, err := json.Marshal(benchReply)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var r mazelib.Reply
+			contents, err := ioutil.ReadAll(bytes.NewReader(data))
+			if err == nil {
+				err = json.Unmarshal(contents, &r)
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, e := range benchEncodings {
+		b.Run(e.name, func(b *testing.B) {
+			var buf replyBuffer
+			buf.json = json.NewEncoder(&buf.Buffer)
+			if err := buf.encode(e.mime, benchReply); err != nil {
+				b.Fatal(err)
+			}
+			body := bytes.NewReader(buf.Bytes())
+			response := &http.Response{Header: http.Header{"Content-Type": {e.mime}}, Body: ioutil.NopCloser(body)}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var r mazelib.Reply
+				body.Reset(buf.Bytes())
+				if err := decodeResponse(response, &r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Spectators can follow the games on the server through the server-sent
+// events at /events, optionally only those of a single ?session=.
+// Every event is named after its type and carries the event as JSON.
+
+// The types of events
+const (
+	// Icarus awoke in a new maze
+	eventAwake = "awake"
+	// Icarus walked into the next room
+	eventMove = "move"
+	// Icarus ran into a wall
+	eventWall = "wall"
+	// Icarus found the treasure
+	eventVictory = "victory"
+)
+
+// event is something that happened in a game
+type event struct {
+	Type      string             `json:"type"`
+	Session   string             `json:"session"`
+	Maze      int                `json:"maze"`
+	Direction string             `json:"direction,omitempty"`
+	Icarus    mazelib.Coordinate `json:"icarus"`
+	Steps     int                `json:"steps"`
+}
+
+// how many events a spectator may fall behind before missing some
+const eventBacklog = 64
+
+// eventHub hands the events of all games to the spectators
+type eventHub struct {
+	sync.Mutex
+	subscribers map[chan event]bool
+	closed      bool
+}
+
+var events = &eventHub{subscribers: map[chan event]bool{}}
+
+// Returns a channel receiving every event from now on.
+// It is closed once the hub is.
+func (h *eventHub) subscribe() chan event {
+	h.Lock()
+	defer h.Unlock()
+	ch := make(chan event, eventBacklog)
+	if h.closed {
+		close(ch)
+		return ch
+	}
+	h.subscribers[ch] = true
+	return ch
+}
+
+func (h *eventHub) unsubscribe(ch chan event) {
+	h.Lock()
+	defer h.Unlock()
+	if h.subscribers[ch] {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Sends the event to all spectators.
+// Spectators which can't keep up miss it rather than holding up the game.
+func (h *eventHub) publish(e event) {
+	h.Lock()
+	defer h.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Ends the streams of all spectators, so the server can shut down
+func (h *eventHub) close() {
+	h.Lock()
+	defer h.Unlock()
+	h.closed = true
+	for ch := range h.subscribers {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Tells the spectators about something that happened in the current maze of the game
+func (g *game) publish(typ, direction string) {
+	events.publish(event{
+		Type:      typ,
+		Session:   g.id,
+		Maze:      g.mazeID,
+		Direction: direction,
+		Icarus:    g.maze.icarus,
+		Steps:     g.maze.StepsTaken,
+	})
+}
+
+// The API response to the /events address
+func StreamEvents(c *Context) {
+	session := c.Query("session")
+	if session != "" {
+		if _, ok := findGameByID(c, session); !ok {
+			respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+			return
+		}
+	}
+	followEvents(c, session)
+}
+
+// Streams the events of the session, or of all games without one, to the client
+func followEvents(c *Context, session string) {
+	ch := events.subscribe()
+	defer events.unsubscribe(ch)
+
+	c.Stream(func(w io.Writer) bool {
+		for {
+			select {
+			case e, ok := <-ch:
+				if !ok {
+					return false
+				}
+				if session != "" && e.Session != session {
+					continue
+				}
+				c.SSEvent(e.Type, e)
+				return true
+			case <-c.Request.Context().Done():
+				return false
+			}
+		}
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// With --export daedalus and icarus write the result of every laybrinth
+// they played to a file, as CSV or JSON depending on its extension, for
+// analysis in other tools.
+// Run together both would write the same file, so only daedalus does.
+
+// whether icarus exports his results
+var icarusExports = true
+
+// Writes the records to the file at path, as JSON or as CSV with the header and rows
+func export(path string, records interface{}, header []string, rows [][]string) error {
+	switch filepath.Ext(path) {
+	case ".json":
+		b, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(path, b, 0644)
+	case ".csv":
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		w := csv.NewWriter(f)
+		w.Write(header)
+		w.WriteAll(rows)
+		if err := w.Error(); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return fmt.Errorf("can't export to %s, use a .csv or .json file", path)
+}
+
+// Exports the results of daedalus
+func exportResults(path string, results []result) error {
+	header := []string{"time", "session", "client", "strategy", "algorithm", "seed", "width", "height", "steps", "solved", "wall_bumps", "timed_out", "duration"}
+	var rows [][]string
+	for _, r := range results {
+		rows = append(rows, []string{
+			r.Time.Format("2006-01-02T15:04:05.000Z07:00"), r.Session, r.Client, r.Strategy, r.Algorithm,
+			strconv.FormatInt(r.Seed, 10), strconv.Itoa(r.Width), strconv.Itoa(r.Height),
+			strconv.Itoa(r.Steps), strconv.FormatBool(r.Solved), strconv.Itoa(r.WallBumps), strconv.FormatBool(r.TimedOut),
+			r.Duration.String(),
+		})
+	}
+	if results == nil {
+		results = []result{}
+	}
+	return export(path, results, header, rows)
+}
+
+// Exports the results of icarus
+func exportSolveStats(path string, stats []solveStats) error {
+	header := []string{"maze", "session", "algorithm", "seed", "width", "height", "steps", "solved", "backtracks", "wall_bumps", "unexplored", "duration"}
+	var rows [][]string
+	for i, s := range stats {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1), s.Session, s.Algorithm,
+			strconv.FormatInt(s.Seed, 10), strconv.Itoa(s.Width), strconv.Itoa(s.Height),
+			strconv.Itoa(s.Steps), strconv.FormatBool(s.Solved), strconv.Itoa(s.Backtracks),
+			strconv.Itoa(s.WallBumps), strconv.Itoa(s.Unexplored), s.Duration.String(),
+		})
+	}
+	if stats == nil {
+		stats = []solveStats{}
+	}
+	return export(path, stats, header, rows)
+}
@@ -0,0 +1,62 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// the settings which have to be numbers within bounds, a max of 0 is no upper bound
+var bounds = []struct {
+	name     string
+	min, max int
+}{
+	{"width", 3, 0},
+	{"height", 3, 0},
+	{"max-width", 3, 0},
+	{"max-height", 3, 0},
+	{"times", 1, 0},
+	{"port", 1, 65535},
+	{"parallel", 1, 0},
+	{"parallel-generation", 1, 0},
+	{"max-steps", 1, 0},
+	{"step-limit", 0, 0},
+	{"retries", 0, 0},
+	{"mazes", 1, 0},
+	{"room-size", 2, 0},
+}
+
+// Checks the numbers given as flags, in the environment or the config
+// file make sense, before any command gets to use them. Only the settings
+// which are flags of the command are checked.
+func checkFlags(flags *pflag.FlagSet) error {
+	for _, b := range bounds {
+		if flags.Lookup(b.name) == nil {
+			continue
+		}
+		v := viper.GetInt(b.name)
+		switch {
+		case b.max > 0 && (v < b.min || v > b.max):
+			return fmt.Errorf("%s has to be between %d and %d, not %d", b.name, b.min, b.max, v)
+		case v < b.min:
+			return fmt.Errorf("%s has to be at least %d, not %d", b.name, b.min, v)
+		}
+	}
+	return nil
+}
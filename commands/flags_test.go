@@ -0,0 +1,40 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// At a max-steps of 0 no maze could ever be solved, and every one would
+// score 0
+func TestCheckFlagsMaxSteps(t *testing.T) {
+	for _, b := range bounds {
+		defer viper.Set(b.name, viper.GetInt(b.name))
+		viper.Set(b.name, b.min)
+	}
+
+	if err := checkFlags(RootCmd.PersistentFlags()); err != nil {
+		t.Fatalf("checkFlags(RootCmd.PersistentFlags()) with every setting at its minimum = %v", err)
+	}
+	viper.Set("max-steps", 0)
+	if err := checkFlags(RootCmd.PersistentFlags()); err == nil || !strings.Contains(err.Error(), "max-steps") {
+		t.Errorf("checkFlags(RootCmd.PersistentFlags()) with max-steps 0 = %v, want it refused", err)
+	}
+}
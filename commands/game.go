@@ -0,0 +1,367 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// game is a session of a single Icarus client on the daedalus server:
+// the maze he is currently solving and the scores of the ones before.
+// The lock has to be held while using any of it.
+type game struct {
+	sync.Mutex
+	id string
+	// the api key the game was started with, if the server requires one
+	client string
+	// the strategy Icarus told us he plays
+	strategy string
+	maze     *Maze
+	// numbers the mazes of the game, the current one has this number
+	mazeID int
+	scores []int
+	// how long each of the scored mazes took
+	durations []time.Duration
+	// the traces of the last keep-traces mazes played before the current
+	// one, by their number
+	traces map[int]mazeTrace
+	// when Icarus last awoke or moved
+	lastActive time.Time
+	// games played in this process don't print their mazes
+	quiet bool
+}
+
+// Icarus may give up on a maze and ask for a new one.
+// Per the scoring rules a maze he didn't solve counts as max-steps steps. It
+// is recorded as one more, to tell it from a maze solved in exactly max-steps.
+func (g *game) retireMaze() {
+	if g.maze != nil && !g.maze.solved && !g.maze.retired {
+		g.maze.retired = true
+		g.record(daedalusConf.MaxSteps + 1)
+	}
+}
+
+// Scores the current maze and adds its result to the store
+func (g *game) record(steps int) {
+	g.scores = append(g.scores, steps)
+	g.durations = append(g.durations, time.Since(g.maze.started))
+	err := store.add(result{
+		Time:      time.Now(),
+		Algorithm: g.maze.algorithm,
+		Seed:      g.maze.seed,
+		Width:     g.maze.Width(),
+		Height:    g.maze.Height(),
+		Steps:     steps,
+		Solved:    g.maze.solved,
+		WallBumps: g.maze.bumps,
+		TimedOut:  g.maze.timedOut,
+		Duration:  time.Since(g.maze.started),
+		Strategy:  g.strategy,
+		Client:    g.client,
+		Session:   g.id,
+	})
+	if err != nil {
+		daedalusLog.Error("couldn't store the result", "session", g.id, "err", err)
+	}
+}
+
+func (g *game) initializeMaze(m *Maze) {
+	m.started = time.Now()
+	g.maze = m
+	g.mazeID++
+
+	// retire the maze in time even if Icarus doesn't come back to it
+	if limit := daedalusConf.TimeLimit; limit > 0 {
+		time.AfterFunc(limit, func() {
+			g.Lock()
+			defer g.Unlock()
+			if g.maze == m {
+				g.timeOut()
+			}
+		})
+	}
+}
+
+// Retires the current maze because Icarus ran out of time
+func (g *game) timeOut() {
+	if !g.maze.solved && !g.maze.retired {
+		g.maze.timedOut = true
+		g.retireMaze()
+	}
+}
+
+// Retires the current maze and lets go of it, keeping only its trace
+func (g *game) dropMaze() {
+	if g.maze == nil {
+		return
+	}
+	g.retireMaze()
+	g.keepTrace()
+	g.maze = nil
+}
+
+// Retires the current maze and places Icarus in a new one, created with the settings.
+// Returns the survey of the room he awakes in.
+func (g *game) startMaze(s mazeSettings) (mazelib.Survey, error) {
+	return g.enterMaze(createMaze(s))
+}
+
+// Retires the current maze and places Icarus in m instead, the same as startMaze
+func (g *game) enterMaze(m *Maze) (mazelib.Survey, error) {
+	g.lastActive = time.Now()
+	g.dropMaze()
+	g.initializeMaze(m)
+	startRoom, err := g.maze.Discover(g.maze.Icarus())
+	if err != nil {
+		daedalusLog.Error("Icarus is outside of the maze. This shouldn't ever happen", "session", g.id, "err", err)
+		return startRoom, err
+	}
+	g.show(nil)
+	g.publish(eventAwake, "")
+	return startRoom, nil
+}
+
+// Prints the maze with the path to mark in it, unless mazes aren't printed
+func (g *game) show(path []mazelib.Coordinate) {
+	if g.quiet || !printsMazes() {
+		return
+	}
+	if err := printMaze(os.Stdout, g.maze, path); err != nil {
+		daedalusLog.Error("The maze can't be printed", "session", g.id, "err", err)
+	}
+}
+
+// Ends the game and returns its results
+func (g *game) finish() mazelib.Results {
+	g.dropMaze()
+	r := mazelib.Results{Mazes: len(g.scores), AverageSteps: mazelib.AvgScores(g.scores), Score: scoring().Score(g.scores).Final}
+	if scoringByTime() && len(g.durations) > 0 {
+		var total time.Duration
+		for _, d := range g.durations {
+			total += d
+		}
+		r.AverageTime = total / time.Duration(len(g.durations))
+	}
+	return r
+}
+
+// gameManager keeps track of every game played on the server
+type gameManager struct {
+	sync.Mutex
+	games map[string]*game
+	// the game started last, used by clients that don't send a session id
+	latest *game
+	// the scores and number of mazes of games which expired, by client
+	expiredScores map[string][]int
+	expiredMazes  int
+	// the games started don't print their mazes
+	quiet bool
+}
+
+var games = &gameManager{games: map[string]*game{}}
+
+// Starts a new game with a fresh id for the given client
+func (gm *gameManager) create(client string) *game {
+	b := make([]byte, 8)
+	rand.Read(b)
+	g := &game{id: hex.EncodeToString(b), client: client, lastActive: time.Now()}
+
+	gm.Lock()
+	defer gm.Unlock()
+	g.quiet = gm.quiet
+	gm.games[g.id] = g
+	gm.latest = g
+	return g
+}
+
+// Finds the game with the given id.
+// An empty id stands for the game started last, which keeps clients
+// that don't know about sessions working as long as they play alone.
+func (gm *gameManager) get(id string) (*game, bool) {
+	gm.Lock()
+	defer gm.Unlock()
+	if id == "" {
+		return gm.latest, gm.latest != nil
+	}
+	g, ok := gm.games[id]
+	return g, ok
+}
+
+// Returns the scores of all games
+func (gm *gameManager) scores() []int {
+	var scores []int
+	for _, s := range gm.scoresByClient() {
+		scores = append(scores, s...)
+	}
+	return scores
+}
+
+// Returns the scores of all games, grouped by the api key they were played with
+func (gm *gameManager) scoresByClient() map[string][]int {
+	gm.Lock()
+	defer gm.Unlock()
+	scores := map[string][]int{}
+	for client, s := range gm.expiredScores {
+		scores[client] = append(scores[client], s...)
+	}
+	for _, g := range gm.games {
+		g.Lock()
+		scores[g.client] = append(scores[g.client], g.scores...)
+		g.Unlock()
+	}
+	return scores
+}
+
+// Returns the number of games started
+func (gm *gameManager) count() int {
+	gm.Lock()
+	defer gm.Unlock()
+	return len(gm.games)
+}
+
+// Returns the number of mazes handed out in all games
+func (gm *gameManager) mazesServed() int {
+	gm.Lock()
+	defer gm.Unlock()
+	served := gm.expiredMazes
+	for _, g := range gm.games {
+		g.Lock()
+		served += g.mazeID
+		g.Unlock()
+	}
+	return served
+}
+
+// Forgets the scores of all games
+func (gm *gameManager) resetScores() {
+	gm.Lock()
+	defer gm.Unlock()
+	gm.expiredScores = nil
+	for _, g := range gm.games {
+		g.Lock()
+		g.scores = nil
+		g.Unlock()
+	}
+}
+
+// Retires the mazes of all games, so unsolved ones count towards the scores
+func (gm *gameManager) retireAll() {
+	gm.Lock()
+	defer gm.Unlock()
+	for _, g := range gm.games {
+		g.Lock()
+		g.dropMaze()
+		g.Unlock()
+	}
+}
+
+// Ends the games Icarus didn't awake or move in for longer than timeout.
+// Their mazes are retired, counting as not finished, and everything but
+// their scores is let go of.
+func (gm *gameManager) expire(timeout time.Duration) {
+	gm.Lock()
+	defer gm.Unlock()
+	for id, g := range gm.games {
+		g.Lock()
+		if time.Since(g.lastActive) > timeout {
+			g.dropMaze()
+			g.notifyEnd("expired")
+			if gm.expiredScores == nil {
+				gm.expiredScores = map[string][]int{}
+			}
+			gm.expiredScores[g.client] = append(gm.expiredScores[g.client], g.scores...)
+			gm.expiredMazes += g.mazeID
+			delete(gm.games, id)
+			if gm.latest == g {
+				gm.latest = nil
+			}
+			daedalusLog.Info("session expired", "session", id, "idle", time.Since(g.lastActive).Round(time.Second))
+		}
+		g.Unlock()
+	}
+}
+
+// Expires idle games every once in a while until the server shuts down
+func (gm *gameManager) expireIdle(timeout time.Duration) {
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			gm.expire(timeout)
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// Finds the game a request belongs to.
+// Games can only be found with the api key they were started with.
+func findGame(c *Context) (*game, bool) {
+	return findGameByID(c, c.Query("session"))
+}
+
+// Finds a game by its id, as long as it was started with the api key of the request
+func findGameByID(c *Context, id string) (*game, bool) {
+	return gameOf(c.GetString("client"), id)
+}
+
+// Finds a game by its id, as long as it was started by client
+func gameOf(client, id string) (*game, bool) {
+	g, ok := games.get(id)
+	if !ok || g.client != client {
+		return nil, false
+	}
+	return g, true
+}
+
+// Only lets requests through which carry one of the configured api keys in
+// their X-API-Key header, and remembers the key as the client playing.
+// Without any keys configured the server is open to everyone.
+func requireAPIKey(c *Context) {
+	keys := daedalusConf.APIKeys
+	if len(keys) == 0 {
+		c.Next()
+		return
+	}
+
+	key := c.GetHeader("X-API-Key")
+	if knownAPIKey(key) {
+		c.Set("client", key)
+		c.Next()
+		return
+	}
+	respond(c, http.StatusUnauthorized, mazelib.Reply{Error: true, Message: "unknown api key"})
+	c.Abort()
+}
+
+// Returns whether key is one of the configured api keys
+func knownAPIKey(key string) bool {
+	for _, k := range daedalusConf.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+// Every /awake without a session starts a game, which are let go of once
+// they idle for longer than the session-timeout, their scores kept
+func TestExpireSessionless(t *testing.T) {
+	gm := &gameManager{games: map[string]*game{}}
+	for i := 0; i < 3; i++ {
+		g := gm.create("")
+		g.scores = []int{10 * (i + 1)}
+		g.lastActive = time.Now().Add(-time.Hour)
+	}
+	active := gm.create("")
+
+	gm.expire(time.Minute)
+	if n := gm.count(); n != 1 {
+		t.Errorf("%d games left after expiring the idle ones, want 1", n)
+	}
+	if g, ok := gm.get(""); !ok || g != active {
+		t.Errorf("the game started last is %v, want the one still active", g)
+	}
+	if scores := gm.scores(); len(scores) != 3 {
+		t.Errorf("scores %v after expiring, want those of the 3 expired games", scores)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the generate command.
+// This will be called as 'laybrinth generate'
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Create a laybrinth without starting a server",
+	Long: `Generate has Daedalus create a single laybrinth with the algorithm,
+  width, height, difficulty and seed asked for and prints it, with
+  box-drawing walls if --charset is unicode. With --out it is written to a
+  file instead, as JSON in the same form as the mazes in traces, for other
+  tools to pick up, or as printed if the file ends in .txt.
+
+  Laybrinths of hundreds of rows are quicker to generate on several cores
+  with --parallel-generation, which carves them in bands of rows at the same
+  time. The same seed makes the same laybrinth with any number above 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := generate(viper.GetString("out")); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	generateCmd.Flags().Int64("seed", 0, "seed the laybrinth is created with (default is a random one)")
+	generateCmd.Flags().StringP("out", "o", "", "file the laybrinth is written to as JSON, or as printed if it ends in .txt (default is to print it)")
+	RootCmd.AddCommand(generateCmd)
+}
+
+// Creates a maze with the configured settings and writes it to the file at path,
+// or prints it without one
+func generate(path string) error {
+	s := currentSettings()
+	s.Seed = viper.GetInt64("seed")
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	m := createMaze(s)
+	if path == "" {
+		if err := printMaze(os.Stdout, m, nil); err != nil {
+			return err
+		}
+		fmt.Printf("%s maze with seed %d\n", m.algorithm, m.seed)
+		return nil
+	}
+	if strings.HasSuffix(path, ".txt") {
+		return ioutil.WriteFile(path, []byte(mazelib.SprintMaze(m)), 0644)
+	}
+	b, err := json.MarshalIndent(m.view(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
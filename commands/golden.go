@@ -0,0 +1,104 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/gen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the golden command.
+// This will be called as 'laybrinth golden <dir>'
+var goldenCmd = &cobra.Command{
+	Use:   "golden <dir>",
+	Short: "Draw a laybrinth of every algorithm to golden files",
+	Long: `Golden generates a laybrinth of the width, height and difficulty asked
+  for with every algorithm, all with the same seed, 1 unless --seed says
+  otherwise, and writes it to dir drawn in every format there is, as text,
+  with box-drawing walls, in color, as SVG, PNG, GIF and dot. The same
+  laybrinth is always drawn the same, so after changing a generator
+  golden --check tells which of the files differ from what it draws now,
+  and exits with 1 if any do. Diffing the text and SVG files shows which
+  walls moved.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+		ok, err := golden(args[0], viper.GetBool("check"))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	goldenCmd.Flags().Int64("seed", 0, "seed the laybrinths are generated with (default is 1)")
+	goldenCmd.Flags().Bool("check", false, "compare the files to what is drawn instead of writing them")
+	RootCmd.AddCommand(goldenCmd)
+}
+
+// Draws a maze of every algorithm to the golden files in dir, or checks
+// them with check. Returns whether all files matched, which they always do
+// if they were written.
+func golden(dir string, check bool) (bool, error) {
+	s := currentSettings()
+	s.Seed = viper.GetInt64("seed")
+	if s.Seed == 0 {
+		s.Seed = 1
+	}
+	if !check {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return false, err
+		}
+	}
+
+	ok := true
+	for _, name := range gen.Names() {
+		s.Algorithm = name
+		if err := s.validate(); err != nil {
+			return false, err
+		}
+		m := createMaze(s)
+		if !check {
+			if err := mazelib.WriteRenders(dir, name, m); err != nil {
+				return false, err
+			}
+			continue
+		}
+		differ, err := mazelib.CheckRenders(dir, name, m)
+		if err != nil {
+			return false, err
+		}
+		for _, file := range differ {
+			fmt.Printf("%s differs\n", file)
+			ok = false
+		}
+	}
+	if ok && check {
+		fmt.Printf("all laybrinths are drawn as in %s\n", dir)
+	}
+	return ok, nil
+}
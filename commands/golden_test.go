@@ -0,0 +1,62 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+var update = flag.Bool("update", false, "write the golden files in testdata instead of comparing to them")
+
+// golden draws the laybrinth of every algorithm as in testdata/golden, and
+// tells when a file differs. After changing a generator or how mazes are
+// drawn, go test -update writes them anew.
+func TestGolden(t *testing.T) {
+	viper.Set("width", 8)
+	viper.Set("height", 6)
+	viper.Set("seed", 1)
+	defer viper.Set("seed", 0)
+	dir := filepath.Join("testdata", "golden")
+
+	if *update {
+		if _, err := golden(dir, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ok, err := golden(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("the laybrinths aren't drawn as in %s, go test -update rewrites them", dir)
+	}
+
+	changed := t.TempDir()
+	if _, err := golden(changed, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(changed, "binarytree.txt"), []byte("|⏀⏅|\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := golden(changed, true); err != nil || ok {
+		t.Errorf("golden of a changed file = %v, %v, want it to differ", ok, err)
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/labyrinthpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Daedalus also speaks the laybrinth protocol over gRPC, with the service of
+// mazelib/labyrinthpb served on grpc-addr next to the REST API.
+// Awake is /awake, Move is /batch, MoveStream is /ws and Done is /done. The
+// api key and the strategy are sent as x-api-key and x-strategy metadata
+// instead of headers. Icarus speaks it with --transport grpc.
+
+// labyrinthServer serves the gRPC calls with the same games as the routes
+type labyrinthServer struct {
+	labyrinthpb.UnimplementedLabyrinthServer
+}
+
+// Serves the laybrinth protocol over gRPC on addr until stopGRPC is called.
+// Returns nil without an addr to serve it on.
+func serveGRPC(addr string) (*grpc.Server, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := grpc.NewServer()
+	labyrinthpb.RegisterLabyrinthServer(s, labyrinthServer{})
+	daedalusLog.Info("serving gRPC", "addr", l.Addr().String())
+	go func() {
+		if err := s.Serve(l); err != nil {
+			daedalusLog.Error("gRPC server failed", "err", err)
+			Shutdown()
+		}
+	}()
+	return s, nil
+}
+
+// Stops the gRPC server, if there is one, letting calls in flight finish
+// until ctx is done
+func stopGRPC(ctx context.Context, s *grpc.Server) {
+	if s == nil {
+		return
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		daedalusLog.Warn("couldn't stop gRPC gracefully", "err", ctx.Err())
+		s.Stop()
+	}
+}
+
+// Returns the first value of the metadata key of an incoming call
+func callMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Returns the client making a call, checking his api key like
+// requireAPIKey does
+func callClient(ctx context.Context) (string, error) {
+	if len(daedalusConf.APIKeys) == 0 {
+		return "", nil
+	}
+	key := callMetadata(ctx, "x-api-key")
+	if !knownAPIKey(key) {
+		return "", status.Error(codes.Unauthenticated, "unknown api key")
+	}
+	return key, nil
+}
+
+func (labyrinthServer) Awake(ctx context.Context, req *labyrinthpb.AwakeRequest) (*labyrinthpb.Reply, error) {
+	client, err := callClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g, ok := gameOf(client, req.GetSession())
+	if req.GetSession() == "" || !ok {
+		g = games.create(client)
+	}
+	g.Lock()
+	defer g.Unlock()
+	g.strategy = callMetadata(ctx, "x-strategy")
+
+	startRoom, err := g.startMaze(currentSettings())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return pbReply(mazelib.Reply{Survey: startRoom, Session: g.id, MazeID: g.mazeID}), nil
+}
+
+func (labyrinthServer) Move(ctx context.Context, req *labyrinthpb.MoveRequest) (*labyrinthpb.MoveReply, error) {
+	client, err := callClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return moveReply(client, req), nil
+}
+
+func (labyrinthServer) MoveStream(stream labyrinthpb.Labyrinth_MoveStreamServer) error {
+	client, err := callClient(stream.Context())
+	if err != nil {
+		return err
+	}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(moveReply(client, req)); err != nil {
+			return err
+		}
+	}
+}
+
+func (labyrinthServer) Done(ctx context.Context, req *labyrinthpb.DoneRequest) (*labyrinthpb.DoneReply, error) {
+	client, err := callClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g, ok := gameOf(client, req.GetSession())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown session")
+	}
+
+	g.Lock()
+	results := g.finish()
+	g.notifyEnd("is done")
+	g.Unlock()
+
+	// the server waits for the reply to go out before it stops
+	if exitOnDone {
+		Shutdown()
+	}
+	return &labyrinthpb.DoneReply{
+		Mazes:         int32(results.Mazes),
+		AverageSteps:  int32(results.AverageSteps),
+		Score:         int32(results.Score),
+		AverageTimeNs: int64(results.AverageTime),
+	}, nil
+}
+
+// Walks the directions of a request in the game of its session and replies
+// to every step, as StreamMoves does
+func moveReply(client string, req *labyrinthpb.MoveRequest) *labyrinthpb.MoveReply {
+	var replies []mazelib.Reply
+	if g, ok := gameOf(client, req.GetSession()); ok {
+		directions := make([]string, len(req.GetDirections()))
+		for i, d := range req.GetDirections() {
+			directions[i] = directionName(d)
+		}
+		replies = g.moveStreamed(directions)
+	} else {
+		replies = []mazelib.Reply{{Error: true, Message: "unknown session, call /awake first", ErrorCode: mazelib.ErrCodeNoActiveMaze}}
+	}
+
+	out := &labyrinthpb.MoveReply{Replies: make([]*labyrinthpb.Reply, len(replies))}
+	for i, r := range replies {
+		out.Replies[i] = pbReply(r)
+	}
+	return out
+}
+
+// The directions of the protocol and the ones of mazelib they stand for
+var pbDirections = map[mazelib.Direction]labyrinthpb.Direction{
+	mazelib.N: labyrinthpb.Direction_UP,
+	mazelib.S: labyrinthpb.Direction_DOWN,
+	mazelib.W: labyrinthpb.Direction_LEFT,
+	mazelib.E: labyrinthpb.Direction_RIGHT,
+}
+
+// Returns the name daedalus moves Icarus by for a direction of the protocol.
+// Those mazelib doesn't know keep their own, which move refuses.
+func directionName(d labyrinthpb.Direction) string {
+	for md, pd := range pbDirections {
+		if pd == d {
+			return md.String()
+		}
+	}
+	return d.String()
+}
+
+func pbReply(r mazelib.Reply) *labyrinthpb.Reply {
+	return &labyrinthpb.Reply{
+		Survey:     &labyrinthpb.Survey{Top: r.Survey.Top, Right: r.Survey.Right, Bottom: r.Survey.Bottom, Left: r.Survey.Left},
+		Victory:    r.Victory,
+		Message:    r.Message,
+		Error:      r.Error,
+		Session:    r.Session,
+		ErrorCode:  r.ErrorCode,
+		WallBumps:  int32(r.WallBumps),
+		MazeId:     int32(r.MazeID),
+		StepsTaken: int32(r.StepsTaken),
+		MoveNumber: int32(r.MoveNumber),
+	}
+}
+
+func replyOf(r *labyrinthpb.Reply) mazelib.Reply {
+	s := r.GetSurvey()
+	return mazelib.Reply{
+		Survey:     mazelib.Survey{Top: s.GetTop(), Right: s.GetRight(), Bottom: s.GetBottom(), Left: s.GetLeft()},
+		Victory:    r.GetVictory(),
+		Message:    r.GetMessage(),
+		Error:      r.GetError(),
+		Session:    r.GetSession(),
+		ErrorCode:  r.GetErrorCode(),
+		WallBumps:  int(r.GetWallBumps()),
+		MazeID:     int(r.GetMazeId()),
+		StepsTaken: int(r.GetStepsTaken()),
+		MoveNumber: int(r.GetMoveNumber()),
+	}
+}
+
+// the connection icarus speaks gRPC to daedalus over, shared by all his
+// sessions, and the client calling the service over it
+var (
+	grpcConn   *grpc.ClientConn
+	grpcClient labyrinthpb.LabyrinthClient
+)
+
+// Has icarus call the gRPC service of daedalus at addr from now on.
+// The connection is only made with the first call.
+func dialGRPC(addr string) error {
+	if addr == "" {
+		return errors.New("the grpc transport needs the grpc-addr daedalus serves gRPC on")
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	if grpcConn != nil {
+		grpcConn.Close()
+	}
+	grpcConn, grpcClient = conn, labyrinthpb.NewLabyrinthClient(conn)
+	return nil
+}
+
+// Returns the context of a call to daedalus, carrying what requestHeader
+// sends along with requests and running out when they would
+func callContext() (context.Context, context.CancelFunc) {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-strategy", icarusConf.Strategy)
+	if key := icarusConf.APIKey; key != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+	}
+	if client.Timeout > 0 {
+		return context.WithTimeout(ctx, client.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Returns the error of a call to method: a connectionError if daedalus
+// couldn't be reached, otherwise a ReplyError telling what he refused.
+func callError(method string, err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return &connectionError{icarusConf.GRPCAddr + method, err}
+	}
+	s, _ := status.FromError(err)
+	return &mazelib.ReplyError{Message: s.Message()}
+}
+
+// Wakes Icarus up in a maze of the session over gRPC
+func (sess *session) awakeRPC() (mazelib.Reply, error) {
+	ctx, cancel := callContext()
+	defer cancel()
+	r, err := grpcClient.Awake(ctx, &labyrinthpb.AwakeRequest{Session: sess.id})
+	if err != nil {
+		return mazelib.Reply{}, callError(labyrinthpb.Labyrinth_Awake_FullMethodName, err)
+	}
+	return replyOf(r), nil
+}
+
+// Sends directions to daedalus over gRPC, returning his replies to every
+// step the same as /batch
+func (sess *session) moveRPC(directions []mazelib.Direction) ([]mazelib.Reply, error) {
+	req := &labyrinthpb.MoveRequest{Session: sess.id, Directions: make([]labyrinthpb.Direction, len(directions))}
+	for i, d := range directions {
+		req.Directions[i] = pbDirections[d]
+	}
+
+	ctx, cancel := callContext()
+	defer cancel()
+	r, err := grpcClient.Move(ctx, req)
+	if err != nil {
+		return nil, callError(labyrinthpb.Labyrinth_Move_FullMethodName, err)
+	}
+	replies := make([]mazelib.Reply, len(r.GetReplies()))
+	for i, rep := range r.GetReplies() {
+		replies[i] = replyOf(rep)
+	}
+	if err := checkReplies(replies, len(directions)); err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+// Tells daedalus over gRPC that the session is done
+func (sess *session) doneRPC() (mazelib.Results, error) {
+	ctx, cancel := callContext()
+	defer cancel()
+	r, err := grpcClient.Done(ctx, &labyrinthpb.DoneRequest{Session: sess.id})
+	if err != nil {
+		return mazelib.Results{}, callError(labyrinthpb.Labyrinth_Done_FullMethodName, err)
+	}
+	return mazelib.Results{
+		Mazes:        int(r.GetMazes()),
+		AverageSteps: int(r.GetAverageSteps()),
+		Score:        int(r.GetScore()),
+		AverageTime:  time.Duration(r.GetAverageTimeNs()),
+	}, nil
+}
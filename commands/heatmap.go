@@ -0,0 +1,59 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import "net/http"
+
+// heatmap counts how often Icarus was in each room of a maze
+type heatmap struct {
+	Session string `json:"session"`
+	Maze    int    `json:"maze"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	// the visits of every room, by row
+	Visits [][]int `json:"visits"`
+	// the visits of the room visited most
+	Max int `json:"max"`
+}
+
+// The API response to the /heatmap address.
+// The maze is picked the same way /trace does.
+func GetHeatmap(c *Context) {
+	if t, ok := findTrace(c); ok {
+		respond(c, http.StatusOK, t.heatmap())
+	}
+}
+
+// Counts the visits of each room along the path of the trace
+func (t mazeTrace) heatmap() heatmap {
+	h := heatmap{
+		Session: t.Session,
+		Maze:    t.Maze,
+		Width:   t.Width,
+		Height:  t.Height,
+		Visits:  make([][]int, t.Height),
+	}
+	for y := range h.Visits {
+		h.Visits[y] = make([]int, t.Width)
+	}
+	for _, c := range t.Path {
+		h.Visits[c.Y][c.X]++
+		if h.Visits[c.Y][c.X] > h.Max {
+			h.Max = h.Visits[c.Y][c.X]
+		}
+	}
+	return h
+}
@@ -0,0 +1,226 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the stats command.
+// This will be called as 'laybrinth stats'
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Analyze the results kept in the scores file",
+	Long: `Stats reads the results Daedalus kept in the scores-file and prints how
+  the average steps developed from day to day, how each algorithm did and the
+  best and worst mazes. The results can be narrowed down to those --since and
+  --until a date and to those of a single --client.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := analyze(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	statsCmd.Flags().String("since", "", "only analyze the results from this date on, like 2015-11-30")
+	statsCmd.Flags().String("until", "", "only analyze the results up to this date, like 2015-12-24")
+	statsCmd.Flags().String("client", "", "only analyze the results of the client with this api key")
+	RootCmd.AddCommand(statsCmd)
+}
+
+// how many of the best and worst mazes are shown
+const extremes = 5
+
+// the layout dates are given and shown in
+const dateLayout = "2006-01-02"
+
+// analysis is what stats found out about the results
+type analysis struct {
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Summary    summary          `json:"summary"`
+	Days       []dayScore       `json:"days"`
+	Algorithms []algorithmScore `json:"algorithms"`
+	Best       []result         `json:"best"`
+	Worst      []result         `json:"worst"`
+}
+
+// Prints the analysis of the stored results the filters let through
+func analyze() error {
+	path := viper.GetString("scores-file")
+	if path == "" {
+		return fmt.Errorf("there are no results to analyze without a scores-file")
+	}
+	s, err := openStore(path)
+	if err != nil {
+		return err
+	}
+	results, err := filterResults(s.all())
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		if jsonOutput() {
+			printJSON(analysis{})
+		} else {
+			fmt.Println("No results to analyze")
+		}
+		return nil
+	}
+
+	a := analyzeResults(results)
+	if jsonOutput() {
+		printJSON(a)
+		return nil
+	}
+
+	var scores []int
+	for _, r := range results {
+		scores = append(scores, r.Steps)
+	}
+	fmt.Printf("%d mazes from %s to %s\n", len(results), a.From, a.To)
+	printSummary(scores, nil)
+
+	fmt.Println("\nBy day:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "day\tmazes\tsolved\tavg steps\t")
+	for _, d := range a.Days {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t\n", d.Day, d.Mazes, d.Solved, d.AverageSteps)
+	}
+	w.Flush()
+
+	fmt.Println("\nBy algorithm:")
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "algorithm\tmazes\tsolved\tavg steps\tscore\t")
+	for _, al := range a.Algorithms {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t\n", al.Algorithm, al.Mazes, al.Solved, al.AverageSteps, al.Score)
+	}
+	w.Flush()
+
+	fmt.Println("\nBest mazes:")
+	printMazes(a.Best)
+	fmt.Println("\nWorst mazes:")
+	printMazes(a.Worst)
+	return nil
+}
+
+// Analyzes the results, which have to be in the order they were played
+func analyzeResults(results []result) analysis {
+	var scores []int
+	for _, r := range results {
+		scores = append(scores, r.Steps)
+	}
+	a := analysis{
+		From:       results[0].Time.Format(dateLayout),
+		To:         results[len(results)-1].Time.Format(dateLayout),
+		Summary:    summarize(scores, nil),
+		Days:       byDay(results),
+		Algorithms: byAlgorithm(results),
+	}
+
+	sorted := append([]result(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Steps < sorted[j].Steps })
+	n := extremes
+	if n > len(sorted) {
+		n = len(sorted)
+	}
+	a.Best = sorted[:n]
+	for i := len(sorted) - 1; i >= len(sorted)-n; i-- {
+		a.Worst = append(a.Worst, sorted[i])
+	}
+	return a
+}
+
+// Returns the results the since, until and client flags let through
+func filterResults(results []result) ([]result, error) {
+	var since, until time.Time
+	if s := viper.GetString("since"); s != "" {
+		t, err := time.ParseInLocation(dateLayout, s, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("since has to be a date like %s", dateLayout)
+		}
+		since = t
+	}
+	if s := viper.GetString("until"); s != "" {
+		t, err := time.ParseInLocation(dateLayout, s, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("until has to be a date like %s", dateLayout)
+		}
+		// until the end of the day
+		until = t.AddDate(0, 0, 1)
+	}
+	client := viper.GetString("client")
+
+	var rs []result
+	for _, r := range results {
+		if (!since.IsZero() && r.Time.Before(since)) || (!until.IsZero() && !r.Time.Before(until)) {
+			continue
+		}
+		if client != "" && r.Client != client {
+			continue
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// dayScore sums up the mazes of a single day
+type dayScore struct {
+	Day          string `json:"day"`
+	Mazes        int    `json:"mazes"`
+	Solved       int    `json:"solved"`
+	AverageSteps int    `json:"average_steps"`
+}
+
+// Sums up the results of every day, in the order of the days
+func byDay(results []result) []dayScore {
+	steps := map[string][]int{}
+	solved := map[string]int{}
+	for _, r := range results {
+		day := r.Time.Local().Format(dateLayout)
+		steps[day] = append(steps[day], r.Steps)
+		if r.Solved {
+			solved[day]++
+		}
+	}
+
+	var ds []dayScore
+	for day, s := range steps {
+		ds = append(ds, dayScore{Day: day, Mazes: len(s), Solved: solved[day], AverageSteps: mazelib.AvgScores(s)})
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i].Day < ds[j].Day })
+	return ds
+}
+
+// Prints a table of the mazes
+func printMazes(results []result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "time\tclient\talgorithm\tsize\tseed\tsteps\tsolved\t")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%dx%d\t%d\t%d\t%t\t\n",
+			r.Time.Local().Format("2006-01-02 15:04:05"), r.Client, r.Algorithm, r.Width, r.Height, r.Seed, r.Steps, r.Solved)
+	}
+	w.Flush()
+}
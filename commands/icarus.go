@@ -16,15 +16,20 @@
 package commands
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
-	"math/rand"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/solve"
+	"github.com/gorilla/websocket"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -46,153 +51,534 @@ var icarusCmd = &cobra.Command{
 	},
 }
 
-var opposite = map[string]string{"up": "down", "down": "up", "left": "right", "right": "left"}
-
 func init() {
 	RootCmd.AddCommand(icarusCmd)
 }
 
 func RunIcarus() {
 	// Run the solver as many times as the user desires.
-	fmt.Println("Solving", viper.GetInt("times"), "times")
+	icarusLog.Info("solving", "times", viper.GetInt("times"), "strategy", viper.GetString("strategy"))
+	if err := configureClient(); err != nil {
+		icarusLog.Error("can't connect to daedalus", "err", err)
+		return
+	}
+	if addr := viper.GetString("pprof-listen"); addr != "" {
+		servePprof(addr)
+	}
+
+	// make sure the strategy exists before starting to play
+	if _, err := newStrategy(viper.GetString("strategy"), nil); err != nil {
+		icarusLog.Error("unknown strategy", "err", err)
+		return
+	}
+
+	mazes := make(chan int, viper.GetInt("times"))
 	for x := 0; x < viper.GetInt("times"); x++ {
+		mazes <- x
+	}
+	close(mazes)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var stats []solveStats
+	exporting := icarusExports && viper.GetString("export") != ""
+	prog := newProgress(viper.GetInt("times"), viper.GetDuration("progress"))
+
+	// solves a single maze, returns an error if the connection got lost
+	solve := func(sess *session) error {
+		strat, _ := newStrategy(viper.GetString("strategy"), nil)
+		s, err := solveMaze(sess, strat, icarusConf.Rooms)
+		s.Session = sess.id
+		if s.Solved && exporting {
+			if r, err := sess.reveal(); err == nil {
+				s.Algorithm, s.Seed, s.Width, s.Height = r.Algorithm, r.Seed, r.Width, r.Height
+			}
+		}
+		mu.Lock()
+		stats = append(stats, s)
+		mu.Unlock()
+		prog.add(s)
+		return err
+	}
+	// solves mazes until there are none left
+	play := func(sess *session) {
+		defer wg.Done()
+		defer sess.close()
+		for range mazes {
+			if err := solve(sess); err != nil {
+				icarusLog.Error("stopped playing", "session", sess.id, "err", err)
+				return
+			}
+		}
+	}
+
+	first := &session{}
+	parallel := viper.GetInt("parallel")
+	if parallel < 1 {
+		parallel = 1
+	}
+	if parallel > 1 {
+		// The first maze is solved on its own to find out if daedalus hands
+		// out sessions. Without them parallel games would get in each
+		// other's way.
+		if _, ok := <-mazes; ok {
+			if err := solve(first); err != nil {
+				icarusLog.Error("stopped playing", "session", first.id, "err", err)
+				parallel = 0
+			}
+		}
+		if parallel > 1 && first.id == "" {
+			icarusLog.Warn("Daedalus doesn't support sessions, solving one laybrinth at a time")
+			parallel = 1
+		}
+	}
+
+	for x := 0; x < parallel; x++ {
+		sess := first
+		if x > 0 {
+			sess = &session{}
+		}
+		wg.Add(1)
+		go play(sess)
+	}
+	wg.Wait()
 
-		solveMaze()
+	if jsonOutput() {
+		printJSON(icarusReport{Mazes: stats})
+	} else {
+		printSolveStats(stats)
+	}
+	if exporting {
+		if err := exportSolveStats(viper.GetString("export"), stats); err != nil {
+			icarusLog.Error("couldn't export the results", "err", err)
+		}
 	}
 
+	saveLearned()
+
 	// Once we have solved the maze the required times, tell daedalus we are done
-	makeRequest("http://127.0.0.1:" + viper.GetString("port") + "/done")
+	first.done()
+}
+
+// labyrinth is what Icarus plays against: a daedalus server, or a game
+// of this process when daedalus and icarus are played in one.
+type labyrinth interface {
+	solve.Mover
+	// the session the mazes are played in
+	sessionID() string
+}
+
+// session is a series of laybrinths solved one after the other.
+// Daedalus servers supporting sessions hand out an id with the first awake,
+// which is sent along with every following request so several sessions can
+// play against the same server at once.
+type session struct {
+	id string
+	// the websocket moves are streamed over, if that transport is used
+	conn *websocket.Conn
+	// the maze daedalus has Icarus in and the number of the last move he
+	// answered in it
+	maze, moves int
+}
+
+func (sess *session) sessionID() string {
+	return sess.id
+}
+
+// Builds the url of a path on the daedalus server for this session
+func (sess *session) url(path string) string {
+	if sess.id == "" {
+		return serverURL(path)
+	}
+	return serverURL(path) + "?session=" + url.QueryEscape(sess.id)
 }
 
 // Make a call to the laybrinth server (daedalus) that icarus is ready to wake up
-func awake() mazelib.Survey {
-	contents, err := makeRequest("http://127.0.0.1:" + viper.GetString("port") + "/awake")
+func (sess *session) Awake() (mazelib.Survey, error) {
+	var r mazelib.Reply
+	var err error
+	if icarusConf.Transport == "grpc" {
+		r, err = sess.awakeRPC()
+	} else {
+		err = makeRequest(sess.url("/awake"), &r)
+	}
 	if err != nil {
-		fmt.Println(err)
+		return mazelib.Survey{}, err
+	}
+	if r.Error {
+		return mazelib.Survey{}, &mazelib.ReplyError{Code: r.ErrorCode, Message: r.Message}
+	}
+	if r.Session != "" {
+		sess.id = r.Session
+	}
+	sess.maze, sess.moves = r.MazeID, 0
+	return r.Survey, nil
+}
+
+// Keeps count of the moves daedalus answered, warning about moves that got
+// lost or answered twice on the way
+func (sess *session) count(rep mazelib.Reply) {
+	if rep.MoveNumber == 0 {
+		// daedalus doesn't number his moves
+		return
+	}
+	if rep.MazeID != sess.maze || rep.MoveNumber != sess.moves+1 {
+		icarusLog.Warn("unexpected move number", "session", sess.id, "maze", rep.MazeID, "move", rep.MoveNumber, "expected", sess.moves+1)
+	}
+	sess.maze, sess.moves = rep.MazeID, rep.MoveNumber
+}
+
+// Asks daedalus to reveal the maze Icarus just solved
+func (sess *session) reveal() (reveal, error) {
+	r := reveal{}
+	err := makeRequest(sess.url("/reveal"), &r)
+	return r, err
+}
+
+// Tells daedalus that Icarus is done with the session, returning the
+// results he replied with
+func (sess *session) done() (mazelib.Results, error) {
+	if icarusConf.Transport == "grpc" {
+		return sess.doneRPC()
 	}
-	r := ToReply(contents)
-	return r.Survey
+	var results mazelib.Results
+	err := makeRequest(sess.url("/done"), &results)
+	return results, err
 }
 
 // Make a call to the laybrinth server (daedalus)
 // to move Icarus a given direction
-// Will be used heavily by solveMaze
-func Move(direction string) (mazelib.Survey, error) {
-	if direction == "left" || direction == "right" || direction == "up" || direction == "down" {
-
-		contents, err := makeRequest("http://127.0.0.1:" + viper.GetString("port") + "/move/" + direction)
-		if err != nil {
+// Will be used heavily by solve.Solve
+func (sess *session) Move(direction mazelib.Direction) (mazelib.Survey, error) {
+	if icarusConf.Transport == "ws" || icarusConf.Transport == "grpc" {
+		surveys, err := sess.MoveBatch([]mazelib.Direction{direction})
+		if len(surveys) == 0 {
 			return mazelib.Survey{}, err
 		}
+		return surveys[0], err
+	}
 
-		rep := ToReply(contents)
-		if rep.Victory == true {
-			fmt.Println(rep.Message)
-			// os.Exit(1)
-			return rep.Survey, mazelib.ErrVictory
-		} else {
-			if rep.Message == "" {
-				return rep.Survey, nil
-			} else {
+	var rep mazelib.Reply
+	if err := fetchReply(sess.url("/move/"+direction.String()), func(in []byte) (err error) {
+		rep, err = ToReply(in)
+		return err
+	}); err != nil {
+		return mazelib.Survey{}, err
+	}
+	sess.count(rep)
+	if rep.Victory {
+		icarusLog.Info(strings.TrimSpace(rep.Message), "session", sess.id)
+		return rep.Survey, mazelib.ErrVictory
+	}
+	if rep.Message != "" {
+		return rep.Survey, &mazelib.ReplyError{Code: rep.ErrorCode, Message: rep.Message}
+	}
+	return rep.Survey, nil
+}
 
-				return rep.Survey, errors.New(rep.Message)
-			}
+// Make a single call to the laybrinth server (daedalus)
+// to move Icarus along a route of several steps.
+// Returns the survey of every room Icarus reached. If a step fails its error
+// is returned and the rest of the route isn't walked. Reaching the treasure
+// returns ErrVictory for the step that got there.
+func (sess *session) MoveBatch(directions []mazelib.Direction) ([]mazelib.Survey, error) {
+	var replies []mazelib.Reply
+	switch icarusConf.Transport {
+	case "ws":
+		var err error
+		if replies, err = sess.stream(names(directions)); err != nil {
+			return nil, err
+		}
+		if err := checkReplies(replies, len(directions)); err != nil {
+			return nil, err
+		}
+	case "grpc":
+		var err error
+		if replies, err = sess.moveRPC(directions); err != nil {
+			return nil, err
+		}
+	default:
+		if err := fetchReply(sess.url("/batch/"+strings.Join(names(directions), ",")), func(in []byte) (err error) {
+			replies, err = toReplies(in, len(directions))
+			return err
+		}); err != nil {
+			return nil, err
 		}
 	}
 
-	return mazelib.Survey{}, errors.New("invalid direction")
+	for _, rep := range replies {
+		sess.count(rep)
+	}
+	return surveysOf(sess.id, replies)
 }
 
-// utility function to wrap making requests to the daedalus server
-func makeRequest(url string) ([]byte, error) {
-	response, err := http.Get(url)
-	if err != nil {
-		return nil, err
+// Returns the surveys of the rooms Icarus reached with the replies to a route,
+// and the error of the step that didn't get him any further, as MoveBatch does.
+func surveysOf(session string, replies []mazelib.Reply) ([]mazelib.Survey, error) {
+	var surveys []mazelib.Survey
+	for _, rep := range replies {
+		if rep.Victory {
+			icarusLog.Info(strings.TrimSpace(rep.Message), "session", session)
+			return surveys, mazelib.ErrVictory
+		}
+		if rep.Error {
+			return surveys, &mazelib.ReplyError{Code: rep.ErrorCode, Message: rep.Message}
+		}
+		surveys = append(surveys, rep.Survey)
 	}
-	defer response.Body.Close()
-	contents, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
+	return surveys, nil
+}
+
+// Walks Icarus along a route, in a single request if batching is enabled.
+// Returns the same as MoveBatch.
+func (sess *session) Walk(directions []mazelib.Direction) ([]mazelib.Survey, error) {
+	if icarusConf.Batch && len(directions) > 1 {
+		return sess.MoveBatch(directions)
+	}
+
+	var surveys []mazelib.Survey
+	for _, d := range directions {
+		s, err := sess.Move(d)
+		if err != nil {
+			return surveys, err
+		}
+		surveys = append(surveys, s)
 	}
-	return contents, nil
+	return surveys, nil
 }
 
-// Handling a JSON response and unmarshalling it into a reply struct
-func ToReply(in []byte) mazelib.Reply {
-	res := &mazelib.Reply{}
-	json.Unmarshal(in, &res)
-	return *res
+// Builds the url of a path on the daedalus server.
+// Without a configured server daedalus is expected on the local machine.
+func serverURL(path string) string {
+	return icarusConf.Server + path
 }
 
-// TODO: This is where you work your magic
-func solveMaze() {
-	s := awake() // Need to start with waking up to initialize a new maze
-	// You'll probably want to set this to a named value and start by figuring
-	// out which step to take next
-	//TODO: Write your solver algorithm here
-	nextMove(s, "")
+// Connects to daedalus, over its Unix socket if there is one
+func dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	d := net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}
+	if path := icarusConf.Socket; path != "" {
+		return d.DialContext(ctx, "unix", path)
+	}
+	return d.DialContext(ctx, network, addr)
+}
+
+// utility function to wrap making requests to the daedalus server
+// The reply is decoded into v, or thrown away if v is nil.
+// Requests which fail to reach daedalus or get a server error in return are
+// retried a few times, waiting twice as long after each failure.
+// Note that a move which reached daedalus, but whose reply got lost, will be
+// walked twice.
+func makeRequest(url string, v interface{}) error {
+	return fetch(url, func(response *http.Response) error {
+		return decodeResponse(response, v)
+	})
 }
 
-// Recursive function. s is the result of the move function, dir the direction we just moved
-func nextMove(s mazelib.Survey, dir string) bool {
-	// try to move in one direction, unless there is a wall
-	// unless it returns the victory error, we call this function recurisvely
-	// returns true if victory
-	// returns false if a dead end (only possible direction is the one we came from)
-	var possibilities []string
+// Makes a request the way makeRequest does, handing the body of the reply to
+// read as it is. It's read into one of the buffers replies are encoded
+// into, which read mustn't hold on to.
+func fetchReply(url string, read func(in []byte) error) error {
+	return fetch(url, func(response *http.Response) error {
+		b := replyBuffers.Get().(*replyBuffer)
+		defer replyBuffers.Put(b)
+		b.Reset()
+		if _, err := b.ReadFrom(response.Body); err != nil {
+			return err
+		}
+		return read(b.Bytes())
+	})
+}
 
-	if !s.Bottom && !(dir == "up") {
-		possibilities = append(possibilities, "down")
+// Requests url, retrying as makeRequest does, and has read read the response
+func fetch(url string, read func(*http.Response) error) error {
+	wait := 100 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		response, err := request(url)
+		if err == nil {
+			defer response.Body.Close()
+			return read(response)
+		}
+		if attempt >= icarusConf.Retries {
+			return &connectionError{url, err}
+		}
+		time.Sleep(wait)
+		if wait *= 2; wait > 5*time.Second {
+			wait = 5 * time.Second
+		}
 	}
-	if !s.Left && !(dir == "right") {
-		possibilities = append(possibilities, "left")
+}
+
+// The client shared by all requests, so connections to daedalus are kept
+// alive and reused instead of being set up for every single move.
+var client = &http.Client{
+	Timeout:   10 * time.Second,
+	Transport: transport,
+}
+
+// the transport of the client, replaced by useTransport
+var transport = newTransport(nil, 4)
+
+// Returns a transport speaking the protocols, the default ones if nil, which
+// keeps conns connections around so every maze icarus solves at the same
+// time has one.
+func newTransport(protocols *http.Protocols, conns int) *http.Transport {
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dial,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   conns,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		Protocols:             protocols,
 	}
-	if !s.Right && !(dir == "left") {
-		possibilities = append(possibilities, "right")
+}
+
+// Has the client send its requests with t from now on.
+// A transport settles on the protocols it speaks with its first request, so
+// changing them takes a new one.
+func useTransport(t *http.Transport) {
+	transport.CloseIdleConnections()
+	transport = t
+	client.Transport = t
+}
+
+// Sets the client up for the timeout, the mazes solved at the same time and
+// the protocol configured, and connects it to daedalus's gRPC service if
+// that is the transport
+func configureClient() error {
+	p, err := clientProtocols(viper.GetString("protocol"))
+	if err != nil {
+		return err
 	}
-	if !s.Top && !(dir == "down") {
-		possibilities = append(possibilities, "up")
+	conns := viper.GetInt("parallel")
+	if conns < 4 {
+		conns = 4
 	}
-	if len(possibilities) == 0 {
-		return false
+	useTransport(newTransport(p, conns))
+	client.Timeout = viper.GetDuration("timeout")
+	if icarusConf.Transport == "grpc" {
+		return dialGRPC(icarusConf.GRPCAddr)
 	}
-	// if there are more then one possible direction, lets shuffle.
+	return nil
+}
 
-	if len(possibilities) > 1 {
-		possibilities = shuffle(possibilities)
+// Returns the protocols icarus speaks to daedalus by the name of one.
+// With h2c he speaks HTTP/2 without TLS right away, which daedalus
+// understands, and all mazes solved at the same time share a connection.
+func clientProtocols(name string) (*http.Protocols, error) {
+	p := new(http.Protocols)
+	switch name {
+	case "http1":
+		p.SetHTTP1(true)
+	case "h2c":
+		p.SetUnencryptedHTTP2(true)
+	default:
+		return nil, fmt.Errorf("unknown protocol %q, use http1 or h2c", name)
 	}
+	return p, nil
+}
 
-	//now try all possibilities
-	for _, d := range possibilities {
-		result, err := Move(d)
-		if err != nil {
-			if err == mazelib.ErrVictory {
-				return true
-			} else {
-				fmt.Println(err.Error())
-			}
-		}
-		if nextMove(result, d) == false {
-			// the move was negative, so lets go back one step
-			if _, err := Move(opposite[d]); err != nil {
-				fmt.Println(err.Error())
-			}
+// Sends the request, returning the response unless daedalus couldn't be
+// reached or replied with a server error. The body is left to be read.
+func request(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the client only reads the headers, so every request can share them
+	req.Header = icarusConf.Header
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 500 {
+		io.Copy(ioutil.Discard, response.Body)
+		response.Body.Close()
+		return nil, fmt.Errorf("daedalus replied with %s", response.Status)
+	}
+	return response, nil
+}
+
+// Returns the headers icarus sends along with every request:
+// the api key authenticating him and the strategy he plays.
+func requestHeader() http.Header {
+	h := http.Header{}
+	if key := icarusConf.APIKey; key != "" {
+		h.Set("X-API-Key", key)
+	}
+	h.Set("X-Strategy", icarusConf.Strategy)
+	return h
+}
 
-		} else {
+// connectionError is returned when daedalus couldn't be reached, even after retrying
+type connectionError struct {
+	url string
+	err error
+}
 
-			return true
-		}
+func (e *connectionError) Error() string {
+	return fmt.Sprintf("lost connection to daedalus requesting %s: %v", e.url, e.err)
+}
+
+// Handling a response and decoding it into a reply struct.
+// Returns the error of a response that isn't a reply.
+func ToReply(in []byte) (mazelib.Reply, error) {
+	var res mazelib.Reply
+	err := decode(in, &res)
+	return res, err
+}
+
+// Decodes the replies to a batch of moves, like ToReply does a single one
+func toReplies(in []byte, moves int) ([]mazelib.Reply, error) {
+	var replies []mazelib.Reply
+	if err := decode(in, &replies); err != nil {
+		return nil, err
+	}
+	if err := checkReplies(replies, moves); err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+// Checks daedalus didn't answer more moves than he was asked for. He can't
+// be trusted with any of them if he did.
+func checkReplies(replies []mazelib.Reply, moves int) error {
+	if len(replies) > moves {
+		return fmt.Errorf("daedalus answered %d moves to the %d asked for", len(replies), moves)
+	}
+	return nil
+}
 
+// TODO: This is where you work your magic
+// Returns an error if the connection to daedalus got lost.
+// The rooms of the maze are only counted to tell how many were left unexplored.
+func solveMaze(sess labyrinth, strat solve.Strategy, rooms int) (solveStats, error) {
+	opts := solve.Options{
+		MaxSteps: viper.GetInt("max-steps"),
+		Rooms:    rooms,
+		Log:      icarusLog.With("session", sess.sessionID()),
+	}
+	if viper.GetBool("watch") {
+		opts.Watch = watch
 	}
-	return false
+	s, err := solve.Solve(sess, strat, opts)
+	return solveStats{Stats: s}, err
+}
+
+// Redraws Icarus's map of the laybrinth in place of the previous one
+func watch(m *solve.Map, pos mazelib.Coordinate) {
+	// move the cursor to the top left and clear the screen
+	fmt.Print("\033[H\033[2J")
+	fmt.Print(m.Render(pos))
+	time.Sleep(viper.GetDuration("watch-delay"))
 }
 
-func shuffle(p []string) []string {
-	rand.Seed(time.Now().UnixNano())
-	temp := make([]string, len(p))
-	t := rand.Perm(len(p))
-	for i, j := range t {
-		temp[i] = p[j]
+// Returns the names of the directions, the way daedalus expects them
+func names(directions []mazelib.Direction) []string {
+	n := make([]string, len(directions))
+	for i, d := range directions {
+		n[i] = d.String()
 	}
-	return temp
+	return n
 }
@@ -0,0 +1,44 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import "testing"
+
+// Replies daedalus, or anyone pretending to be him, sends never crash icarus,
+// and a batch answered with more replies than moves is refused
+func FuzzToReply(f *testing.F) {
+	f.Add([]byte(`{"survey":{"top":true,"right":false,"bottom":true,"left":false},"victory":false,"message":"","error":false}`))
+	f.Add([]byte(`{"survey":{},"victory":true,"message":"Victory achieved in 12 steps"}`))
+	f.Add([]byte(`{"error":true,"message":"Can't walk through walls","error_code":"wall"}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`[{"survey":{}},{"survey":{}},{"survey":{},"victory":true}]`))
+	f.Add([]byte(`{"survey":`))
+	f.Fuzz(func(t *testing.T, in []byte) {
+		ToReply(in)
+
+		const moves = 2
+		replies, err := toReplies(in, moves)
+		if err != nil {
+			return
+		}
+		if len(replies) > moves {
+			t.Fatalf("took %d replies to %d moves", len(replies), moves)
+		}
+		if surveys, _ := surveysOf("", replies); len(surveys) > len(replies) {
+			t.Fatalf("got %d surveys of %d replies", len(surveys), len(replies))
+		}
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib/solve"
+)
+
+// solveStats is how Icarus did in a single maze, and which maze it was
+type solveStats struct {
+	solve.Stats
+	Session string `json:"session,omitempty"`
+	// the maze daedalus generated, as he reveals it once it is solved
+	Algorithm string `json:"algorithm,omitempty"`
+	Seed      int64  `json:"seed,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+}
+
+// Prints a table with a row per maze followed by the averages over all mazes
+func printSolveStats(stats []solveStats) {
+	if len(stats) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "maze\tsolved\tsteps\tbacktracks\twall bumps\ttime\tunexplored\t")
+
+	var total solveStats
+	solved := 0
+	for i, s := range stats {
+		fmt.Fprintf(w, "%d\t%t\t%d\t%d\t%d\t%s\t%d\t\n",
+			i+1, s.Solved, s.Steps, s.Backtracks, s.WallBumps, s.Duration, s.Unexplored)
+		if s.Solved {
+			solved++
+		}
+		total.Steps += s.Steps
+		total.Backtracks += s.Backtracks
+		total.WallBumps += s.WallBumps
+		total.Duration += s.Duration
+		total.Unexplored += s.Unexplored
+	}
+
+	n := len(stats)
+	fmt.Fprintf(w, "avg\t%d/%d\t%d\t%d\t%d\t%s\t%d\t\n",
+		solved, n, total.Steps/n, total.Backtracks/n, total.WallBumps/n,
+		total.Duration/time.Duration(n), total.Unexplored/n)
+	w.Flush()
+}
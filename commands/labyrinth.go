@@ -21,6 +21,7 @@ package commands
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"time"
 
@@ -52,38 +53,143 @@ if there is a wall or not to the top, right, bottom and left. He takes
 one step and then can discover if his new cell has walls on each of
 the four sides.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		go RunServer()
-
-		// give server time to start before sending a request.
-		// There's a better way to do this, but I'm lazy and this is just for fun.
-		time.Sleep(1 * time.Second)
-
-		RunIcarus()
+		RunBoth()
+	},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		initConfig(cmd)
 	},
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
-
 	// Setting flags here so they can be used by both the root behavior as well as
-	// by the indidual behaviors of icarus and daedalus
+	// by the indidual behaviors of icarus and daedalus. Flags only a single
+	// command has are set by that command.
 	RootCmd.PersistentFlags().StringVar(&CfgFile, "config", "", "config file (default is $CWD/config.yaml)")
-	RootCmd.PersistentFlags().IntP("port", "p", 8013, "Port run on")
-	RootCmd.PersistentFlags().IntP("width", "x", 15, "width of the laybrinth")
-	RootCmd.PersistentFlags().IntP("height", "y", 10, "height of the laybrinth") // 'h' is used for help already
-	RootCmd.PersistentFlags().IntP("times", "t", 1, "times to solve the laybrinth")
-	RootCmd.PersistentFlags().IntP("max-steps", "m", 500, "Maximum steps before giving up")
+	RootCmd.PersistentFlags().IntP("port", "p", 8013, "port daedalus listens on and icarus connects to, 1 to 65535")
+	RootCmd.PersistentFlags().String("log-level", "info", "least important messages logged (debug, info, warn, error)")
+	RootCmd.PersistentFlags().String("log-format", "text", "format of the log (text, json)")
+	RootCmd.PersistentFlags().String("output", "text", "how results are written to stdout: text, or json for scripts")
+	RootCmd.PersistentFlags().String("charset", "ascii", "characters mazes are drawn with: ascii, or unicode for box-drawing walls")
+	RootCmd.PersistentFlags().String("window", "100x100", "most rooms of a laybrinth printed across and down, larger ones are printed around icarus (0x0 prints them whole)")
+	RootCmd.PersistentFlags().Bool("color", false, "draw mazes in color, with icarus and the way he took, for terminals understanding ANSI escape codes")
+	RootCmd.PersistentFlags().BoolP("quiet", "q", false, "leave out the mazes and the logs of every maze and move")
+	RootCmd.PersistentFlags().String("addr", "", "address daedalus listens on, host:port or unix:<path> for a Unix socket (default is :<port>)")
+	RootCmd.PersistentFlags().String("grpc-addr", "", "host:port daedalus also serves the laybrinth protocol over gRPC on, and icarus connects to with --transport grpc (default is not to serve gRPC)")
+	RootCmd.PersistentFlags().StringSlice("api-keys", nil, "api keys daedalus accepts from icarus clients (default is to accept everyone)")
+	RootCmd.PersistentFlags().String("api-key", "", "api key icarus authenticates with")
+	RootCmd.PersistentFlags().String("admin-key", "", "key the admin endpoints of daedalus require (default is to disable them)")
+	RootCmd.PersistentFlags().String("server", "", "url of the daedalus server icarus connects to (default is http://127.0.0.1:<port>, unix:<path> for a Unix socket)")
+	RootCmd.PersistentFlags().Bool("exit-on-done", true, "shut daedalus down once icarus is done")
+	RootCmd.PersistentFlags().Bool("ui", false, "serve a dashboard showing the laybrinths being solved at /ui, which gives them away to anyone watching")
+	RootCmd.PersistentFlags().Bool("play-page", false, "serve a page at /play where laybrinths can be solved by hand in the browser")
+	RootCmd.PersistentFlags().String("trace-dir", "", "directory daedalus writes the trace of every laybrinth to (default is to only keep the last keep-traces in memory)")
+	RootCmd.PersistentFlags().Int("keep-traces", 10, "number of finished laybrinths of a session daedalus keeps the traces of in memory for /trace")
+	RootCmd.PersistentFlags().Bool("pprof", false, "serve profiles of daedalus at /debug/pprof/")
+	RootCmd.PersistentFlags().String("pprof-listen", "", "address icarus serves his profiles on at /debug/pprof/, like localhost:6060")
+	RootCmd.PersistentFlags().String("score-by", "steps", "what mazes are scored by: steps, or time to report how long each took alongside its steps")
+	RootCmd.PersistentFlags().Int("wall-penalty", 0, "steps added to the score of a maze for every move daedalus refused in it")
+	RootCmd.PersistentFlags().String("webhook", "", "url daedalus posts a Slack compatible message to when a session ends or a maze is solved in fewer steps than ever")
+	RootCmd.PersistentFlags().String("leaderboard-url", "", "url the final results are submitted to, if any")
+	RootCmd.PersistentFlags().String("leaderboard-secret", "", "secret the results submitted to the leaderboard are signed with")
+	RootCmd.PersistentFlags().String("export", "", "file to export the result of every laybrinth played to when done, as .csv or .json")
+	RootCmd.PersistentFlags().String("scores-file", "", "file daedalus keeps the results of every laybrinth in (default is to only keep them in memory)")
+	RootCmd.PersistentFlags().Int("retries", 3, "times icarus retries a request daedalus didn't answer")
+	RootCmd.PersistentFlags().Duration("timeout", 10*time.Second, "time icarus waits for daedalus to answer a request")
+	RootCmd.PersistentFlags().IntP("width", "x", 15, "width of the laybrinth in rooms, at least 3")
+	RootCmd.PersistentFlags().IntP("height", "y", 10, "height of the laybrinth in rooms, at least 3") // 'h' is used for help already
+	RootCmd.PersistentFlags().Int("max-width", 50, "widest laybrinth icarus may ask daedalus for")
+	RootCmd.PersistentFlags().Int("max-height", 50, "highest laybrinth icarus may ask daedalus for")
+	RootCmd.PersistentFlags().String("algorithm", "random", "algorithm daedalus generates laybrinths with (random, binarytree, binarytree-holes, growingtree)")
+	RootCmd.PersistentFlags().String("difficulty", "normal", "how far from the treasure icarus awakes (easy, normal, hard, extreme for the ends of the longest way through the laybrinth)")
+	RootCmd.PersistentFlags().IntP("times", "t", 1, "times to solve the laybrinth, at least 1")
+	RootCmd.PersistentFlags().Int("parallel", 1, "number of laybrinths icarus solves at the same time")
+	RootCmd.PersistentFlags().Int("parallel-generation", 1, "bands of rows laybrinths of 256 rows and more are carved in at the same time (default is to carve them in one go)")
+	RootCmd.PersistentFlags().IntP("max-steps", "m", 500, "Maximum steps before giving up on a laybrinth")
+	RootCmd.PersistentFlags().Int("evaluation-mazes", 0, "laybrinths an evaluation is scored on, missing ones count as max-steps (default is all laybrinths played)")
+	RootCmd.PersistentFlags().Int("step-limit", 0, "steps daedalus allows in a laybrinth before ending it (default is no limit)")
+	RootCmd.PersistentFlags().Duration("session-timeout", 10*time.Minute, "time after which daedalus ends sessions icarus doesn't awake or move in, keeping their scores")
+	RootCmd.PersistentFlags().Duration("time-limit", 0, "time daedalus allows for a laybrinth before ending it (default is no limit)")
+	RootCmd.PersistentFlags().StringP("strategy", "s", "dfs", "strategy icarus uses to explore the laybrinth (dfs, montecarlo, qlearning)")
+	RootCmd.PersistentFlags().Float64("temperature", 2, "how much the montecarlo strategy wanders off the most promising path")
+	RootCmd.PersistentFlags().Duration("progress", 10*time.Second, "how often icarus logs how far he got, and when he'll be done (0 to never)")
+	RootCmd.PersistentFlags().String("router", "", "router daedalus serves with, gin or mux for net/http's ServeMux (default is gin, or mux if built with the nogin tag)")
+	RootCmd.PersistentFlags().String("protocol", "http1", "protocol icarus speaks to daedalus over http (http1, h2c for HTTP/2 without TLS)")
+	RootCmd.PersistentFlags().String("transport", "http", "how icarus sends his moves to daedalus (http, ws, grpc)")
+	RootCmd.PersistentFlags().String("encoding", "json", "encoding icarus asks daedalus to reply in (json, msgpack, cbor)")
+	RootCmd.PersistentFlags().Bool("batch", true, "walk routes through known rooms with a single request")
+	RootCmd.PersistentFlags().BoolP("watch", "w", false, "draw icarus's map of the laybrinth after every move")
+	RootCmd.PersistentFlags().Duration("watch-delay", 100*time.Millisecond, "time to pause after every redraw in watch mode, and every frame of replay or a GIF")
+	RootCmd.PersistentFlags().String("qtable", "qtable.json", "file the qlearning strategy keeps what it learned in")
+	RootCmd.PersistentFlags().Float64("epsilon", 0.1, "how often the qlearning strategy tries a random direction")
+	RootCmd.PersistentFlags().Float64("bias-straight", 0, "how much the dfs strategy prefers walking straight on")
+	RootCmd.PersistentFlags().Float64("bias-center", 0, "how much the dfs strategy prefers heading towards the center of the laybrinth")
+	RootCmd.PersistentFlags().Float64("bias-spiral", 0, "how much the dfs strategy prefers turning clockwise, spiralling outwards")
 
 	// Bind viper to these flags so viper can read flag values along with config, env, etc.
 	viper.BindPFlag("width", RootCmd.PersistentFlags().Lookup("width"))
 	viper.BindPFlag("height", RootCmd.PersistentFlags().Lookup("height"))
+	viper.BindPFlag("max-width", RootCmd.PersistentFlags().Lookup("max-width"))
+	viper.BindPFlag("max-height", RootCmd.PersistentFlags().Lookup("max-height"))
+	viper.BindPFlag("algorithm", RootCmd.PersistentFlags().Lookup("algorithm"))
+	viper.BindPFlag("difficulty", RootCmd.PersistentFlags().Lookup("difficulty"))
 	viper.BindPFlag("port", RootCmd.PersistentFlags().Lookup("port"))
+	viper.BindPFlag("log-level", RootCmd.PersistentFlags().Lookup("log-level"))
+	viper.BindPFlag("log-format", RootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("output", RootCmd.PersistentFlags().Lookup("output"))
+	viper.BindPFlag("charset", RootCmd.PersistentFlags().Lookup("charset"))
+	viper.BindPFlag("window", RootCmd.PersistentFlags().Lookup("window"))
+	viper.BindPFlag("color", RootCmd.PersistentFlags().Lookup("color"))
+	viper.BindPFlag("quiet", RootCmd.PersistentFlags().Lookup("quiet"))
+	viper.BindPFlag("addr", RootCmd.PersistentFlags().Lookup("addr"))
+	viper.BindPFlag("grpc-addr", RootCmd.PersistentFlags().Lookup("grpc-addr"))
+	viper.BindPFlag("exit-on-done", RootCmd.PersistentFlags().Lookup("exit-on-done"))
+	viper.BindPFlag("ui", RootCmd.PersistentFlags().Lookup("ui"))
+	viper.BindPFlag("play-page", RootCmd.PersistentFlags().Lookup("play-page"))
+	viper.BindPFlag("trace-dir", RootCmd.PersistentFlags().Lookup("trace-dir"))
+	viper.BindPFlag("keep-traces", RootCmd.PersistentFlags().Lookup("keep-traces"))
+	viper.BindPFlag("pprof", RootCmd.PersistentFlags().Lookup("pprof"))
+	viper.BindPFlag("pprof-listen", RootCmd.PersistentFlags().Lookup("pprof-listen"))
+	viper.BindPFlag("score-by", RootCmd.PersistentFlags().Lookup("score-by"))
+	viper.BindPFlag("wall-penalty", RootCmd.PersistentFlags().Lookup("wall-penalty"))
+	viper.BindPFlag("webhook", RootCmd.PersistentFlags().Lookup("webhook"))
+	viper.BindPFlag("leaderboard-url", RootCmd.PersistentFlags().Lookup("leaderboard-url"))
+	viper.BindPFlag("leaderboard-secret", RootCmd.PersistentFlags().Lookup("leaderboard-secret"))
+	viper.BindPFlag("export", RootCmd.PersistentFlags().Lookup("export"))
+	viper.BindPFlag("scores-file", RootCmd.PersistentFlags().Lookup("scores-file"))
+	viper.BindPFlag("retries", RootCmd.PersistentFlags().Lookup("retries"))
+	viper.BindPFlag("timeout", RootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("api-keys", RootCmd.PersistentFlags().Lookup("api-keys"))
+	viper.BindPFlag("api-key", RootCmd.PersistentFlags().Lookup("api-key"))
+	viper.BindPFlag("admin-key", RootCmd.PersistentFlags().Lookup("admin-key"))
+	viper.BindPFlag("server", RootCmd.PersistentFlags().Lookup("server"))
 	viper.BindPFlag("times", RootCmd.PersistentFlags().Lookup("times"))
+	viper.BindPFlag("parallel", RootCmd.PersistentFlags().Lookup("parallel"))
+	viper.BindPFlag("parallel-generation", RootCmd.PersistentFlags().Lookup("parallel-generation"))
 	viper.BindPFlag("max-steps", RootCmd.PersistentFlags().Lookup("max-steps"))
+	viper.BindPFlag("evaluation-mazes", RootCmd.PersistentFlags().Lookup("evaluation-mazes"))
+	viper.BindPFlag("step-limit", RootCmd.PersistentFlags().Lookup("step-limit"))
+	viper.BindPFlag("session-timeout", RootCmd.PersistentFlags().Lookup("session-timeout"))
+	viper.BindPFlag("time-limit", RootCmd.PersistentFlags().Lookup("time-limit"))
+	viper.BindPFlag("strategy", RootCmd.PersistentFlags().Lookup("strategy"))
+	viper.BindPFlag("temperature", RootCmd.PersistentFlags().Lookup("temperature"))
+	viper.BindPFlag("progress", RootCmd.PersistentFlags().Lookup("progress"))
+	viper.BindPFlag("router", RootCmd.PersistentFlags().Lookup("router"))
+	viper.BindPFlag("protocol", RootCmd.PersistentFlags().Lookup("protocol"))
+	viper.BindPFlag("transport", RootCmd.PersistentFlags().Lookup("transport"))
+	viper.BindPFlag("encoding", RootCmd.PersistentFlags().Lookup("encoding"))
+	viper.BindPFlag("batch", RootCmd.PersistentFlags().Lookup("batch"))
+	viper.BindPFlag("watch", RootCmd.PersistentFlags().Lookup("watch"))
+	viper.BindPFlag("watch-delay", RootCmd.PersistentFlags().Lookup("watch-delay"))
+	viper.BindPFlag("qtable", RootCmd.PersistentFlags().Lookup("qtable"))
+	viper.BindPFlag("epsilon", RootCmd.PersistentFlags().Lookup("epsilon"))
+	viper.BindPFlag("bias-straight", RootCmd.PersistentFlags().Lookup("bias-straight"))
+	viper.BindPFlag("bias-center", RootCmd.PersistentFlags().Lookup("bias-center"))
+	viper.BindPFlag("bias-spiral", RootCmd.PersistentFlags().Lookup("bias-spiral"))
 }
 
-// Read in config file and ENV variables if set.
-func initConfig() {
+// Read in config file and ENV variables if set, and check the settings the
+// command about to run has.
+func initConfig(cmd *cobra.Command) {
 	if CfgFile != "" {
 		viper.SetConfigFile(CfgFile)
 	}
@@ -93,10 +199,28 @@ func initConfig() {
 	viper.AddConfigPath(dir)
 
 	viper.AutomaticEnv() // read in environment variables that match
+	// several commands have flags of the same name, like --out, so only
+	// those of the one running are bound
+	viper.BindPFlags(cmd.LocalNonPersistentFlags())
 
 	// If a config.yaml file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+	configErr := viper.ReadInConfig()
+
+	if err := checkFlags(cmd.Flags()); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+	loadConfig()
+	if err := checkOutput(); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+	if err := setupLogging(); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+	if configErr == nil {
+		slog.Info("using config file", "file", viper.ConfigFileUsed())
 	}
 }
 
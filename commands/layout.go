@@ -0,0 +1,59 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Mazes are saved as their mazelib.Layout, the way the mazes in traces are,
+// without anything about the game played in them.
+
+// MarshalJSON writes the layout of the maze
+func (m *Maze) MarshalJSON() ([]byte, error) {
+	l, err := mazelib.LayoutOf(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(l)
+}
+
+// UnmarshalJSON reads a maze written by MarshalJSON, or the maze of a trace,
+// with Icarus at its start
+func (m *Maze) UnmarshalJSON(b []byte) error {
+	var l mazelib.Layout
+	if err := json.Unmarshal(b, &l); err != nil {
+		return err
+	}
+	// NewEmptyMaze needs a room to make a maze of
+	if err := l.Whole(); err != nil {
+		return fmt.Errorf("maze %v", err)
+	}
+	z := NewEmptyMaze(l.Width, l.Height)
+	if err := l.Build(z); err != nil {
+		return err
+	}
+	*m = *z
+	return nil
+}
+
+// Returns the layout of the maze in the view
+func (v mazeView) layout() mazelib.Layout {
+	return mazelib.Layout{Width: v.Width, Height: v.Height, Walls: v.Walls, Start: v.Start, Treasure: v.Treasure}
+}
@@ -0,0 +1,66 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// A maze that loads from JSON is whole, with its start and treasure in it,
+// and is written back as it was read
+func FuzzMazeUnmarshalJSON(f *testing.F) {
+	m := NewEmptyMaze(3, 2)
+	m.SetTreasure(2, 1)
+	m.SetStartPoint(0, 0)
+	b, err := json.Marshal(m)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(b)
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"width":2,"height":1,"walls":[[{}]]}`))
+	f.Add([]byte(`{"width":-1,"height":-1,"walls":[]}`))
+	f.Add([]byte(`{"width":2,"height":1,"walls":[[{},{}]],"start":{"x":0,"y":0},"treasure":{"x":5,"y":0}}`))
+	f.Add([]byte(`{"width":2,"height":1,"walls":[[{},{}]],"start":{"x":1,"y":0},"treasure":{"x":1,"y":0}}`))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var m Maze
+		if err := json.Unmarshal(b, &m); err != nil {
+			return
+		}
+		l, err := mazelib.LayoutOf(&m)
+		if err != nil {
+			t.Fatalf("can't lay out the maze loaded: %v", err)
+		}
+		if err := l.Placed(); err != nil {
+			t.Fatalf("loaded a maze that %v", err)
+		}
+		out, err := json.Marshal(&m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var again Maze
+		if err := json.Unmarshal(out, &again); err != nil {
+			t.Fatalf("can't load the maze written:\n%s\n%v", out, err)
+		}
+		if la, _ := mazelib.LayoutOf(&again); !reflect.DeepEqual(l, la) {
+			t.Fatalf("the maze loaded again differs: %+v\n%+v", l, la)
+		}
+	})
+}
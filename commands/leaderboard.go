@@ -0,0 +1,124 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/viper"
+)
+
+// With a leaderboard-url configured the final results of a run are posted
+// there as JSON, so the scores of everyone taking the challenge can be
+// collected in one place. With a leaderboard-secret the payload is signed
+// with HMAC-SHA256, the hex signature is sent in the X-Signature header as
+// sha256=<signature>, so the leaderboard can tell the results weren't faked.
+
+// submission is what is posted to the leaderboard
+type submission struct {
+	Author   string        `json:"author"`
+	Strategy string        `json:"strategy,omitempty"`
+	Time     time.Time     `json:"time"`
+	Width    int           `json:"width"`
+	Height   int           `json:"height"`
+	Score    mazelib.Score `json:"score"`
+	Stats    mazelib.Stats `json:"stats"`
+	// the build the results were played with
+	Version  string `json:"version"`
+	Commit   string `json:"commit"`
+	Protocol int    `json:"protocol"`
+}
+
+var leaderboardClient = &http.Client{Timeout: 10 * time.Second}
+
+// Submits the scores to the leaderboard, if one is configured
+func submitResults(scores []int, strategy string) {
+	url := viper.GetString("leaderboard-url")
+	if url == "" {
+		return
+	}
+
+	s := submission{
+		Author:   AuthorName,
+		Strategy: strategy,
+		Time:     time.Now(),
+		Width:    currentSettings().Width,
+		Height:   currentSettings().Height,
+		Score:    scoring().Score(scores),
+		Stats:    mazelib.Statistics(scores, histogramBuckets),
+		Version:  Version,
+		Commit:   Commit,
+		Protocol: mazelib.ProtocolVersion,
+	}
+	if err := submit(url, viper.GetString("leaderboard-secret"), s); err != nil {
+		daedalusLog.Error("couldn't submit the results to the leaderboard", "url", url, "err", err)
+		return
+	}
+	daedalusLog.Info("submitted the results to the leaderboard", "url", url, "score", s.Score.Final)
+}
+
+// Returns the strategy all results were played with, or nothing if it isn't just one
+func onlyStrategy(results []result) string {
+	strategy, seen := "", false
+	for _, r := range results {
+		if seen && r.Strategy != strategy {
+			return ""
+		}
+		strategy, seen = r.Strategy, true
+	}
+	return strategy
+}
+
+// Posts the submission, signed with the secret unless it's empty
+func submit(url, secret string, s submission) error {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mimeJSON)
+	if secret != "" {
+		req.Header.Set("X-Signature", "sha256="+sign(secret, body))
+	}
+
+	response, err := leaderboardClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("the leaderboard replied with %s", response.Status)
+	}
+	return nil
+}
+
+// Returns the hex encoded HMAC-SHA256 of the body
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
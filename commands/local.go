@@ -0,0 +1,61 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// localGame lets Icarus play a game of daedalus in this process, without
+// going through HTTP. The games aren't registered with the server, so
+// nobody else can join them.
+type localGame struct {
+	game     *game
+	settings mazeSettings
+	// the maze Icarus awakes in every time instead of a new one, if set
+	maze *mazeView
+}
+
+// Creates a game of Icarus playing the strategy in mazes created with the settings
+func newLocalGame(id, strategy string, s mazeSettings) *localGame {
+	return &localGame{game: &game{id: id, strategy: strategy, quiet: true}, settings: s}
+}
+
+func (l *localGame) Awake() (mazelib.Survey, error) {
+	l.game.Lock()
+	defer l.game.Unlock()
+	if l.maze != nil {
+		return l.game.enterMaze(l.maze.maze())
+	}
+	return l.game.startMaze(l.settings)
+}
+
+func (l *localGame) Walk(directions []mazelib.Direction) ([]mazelib.Survey, error) {
+	l.game.Lock()
+	defer l.game.Unlock()
+	return surveysOf(l.game.id, l.game.moveAll(names(directions)))
+}
+
+func (l *localGame) sessionID() string {
+	return l.game.id
+}
+
+// Ends the game and returns its results, like daedalus does when Icarus is done
+func (l *localGame) finish() mazelib.Results {
+	l.game.Lock()
+	defer l.game.Unlock()
+	return l.game.finish()
+}
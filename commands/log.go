@@ -0,0 +1,64 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Daedalus and icarus log what they are doing to stderr, leaving stdout to
+// the mazes, maps and results they print. Every line carries the component
+// it comes from, and dropping the log-level to debug shows every move,
+// like the walls icarus bumps into.
+
+var (
+	daedalusLog = slog.Default().With("component", "daedalus")
+	icarusLog   = slog.Default().With("component", "icarus")
+)
+
+// Sets up the loggers as the log-level and log-format flags ask for
+func setupLogging() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(viper.GetString("log-level"))); err != nil {
+		return fmt.Errorf("unknown log level %q, use debug, info, warn or error", viper.GetString("log-level"))
+	}
+	if viper.GetBool("quiet") && level < slog.LevelWarn {
+		// what is logged at info is mostly about single mazes
+		level = slog.LevelWarn
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	switch strings.ToLower(viper.GetString("log-format")) {
+	case "text":
+		h = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("unknown log format %q, use text or json", viper.GetString("log-format"))
+	}
+
+	logger := slog.New(h)
+	slog.SetDefault(logger)
+	daedalusLog = logger.With("component", "daedalus")
+	icarusLog = logger.With("component", "icarus")
+	return nil
+}
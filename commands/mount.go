@@ -0,0 +1,76 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net/http"
+	"strings"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/solve"
+	"github.com/spf13/viper"
+)
+
+// Mount adds the routes of daedalus to mux, so another server can serve
+// them, like an httptest.Server playing a whole game in a test. The
+// settings are read from viper the way RunServer reads them, but nothing
+// is listened on, the scores stay in memory and /done ends the session
+// without shutting anything down. Idle sessions expire after session-timeout,
+// as they do on RunServer.
+func Mount(mux *http.ServeMux) error {
+	loadConfig()
+	if err := checkServing(); err != nil {
+		return err
+	}
+	// a client's /done must never shut down the server mounting the routes
+	exitOnDone = false
+	go games.expireIdle(viper.GetDuration("session-timeout"))
+	mountRoutes(mux, daedalusRoutes())
+	return nil
+}
+
+// PlayAgainst has icarus solve times laybrinths with the strategy on the
+// daedalus at server, like one of RunIcarus's sessions, and tell him he is
+// done. Returns how Icarus did in every maze and the results daedalus
+// replied to /done with. Icarus keeps talking to server afterwards.
+func PlayAgainst(server, strategy string, times int) ([]solve.Stats, mazelib.Results, error) {
+	var results mazelib.Results
+	loadConfig()
+	if err := configureClient(); err != nil {
+		return nil, results, err
+	}
+	icarusConf.Server, icarusConf.Socket = strings.TrimSuffix(server, "/"), ""
+	icarusConf.Strategy = strategy
+	// requests still in flight keep the header they were sent with
+	icarusConf.Header = icarusHeader()
+
+	sess := &session{}
+	defer sess.close()
+	var stats []solve.Stats
+	for x := 0; x < times; x++ {
+		strat, err := newStrategy(strategy, nil)
+		if err != nil {
+			return stats, results, err
+		}
+		s, err := solveMaze(sess, strat, icarusConf.Rooms)
+		if err != nil {
+			return stats, results, err
+		}
+		stats = append(stats, s.Stats)
+	}
+	results, err := sess.done()
+	return stats, results, err
+}
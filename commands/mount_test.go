@@ -0,0 +1,75 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// A whole game played in-process against the routes mounted on a test
+// server, which keeps serving after /done
+func TestPlayAgainst(t *testing.T) {
+	viper.Set("width", 10)
+	viper.Set("height", 8)
+	viper.Set("quiet", true)
+	viper.Set("exit-on-done", true)
+	defer viper.Set("exit-on-done", false)
+
+	mux := http.NewServeMux()
+	if err := Mount(mux); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	const times = 3
+	stats, results, err := PlayAgainst(srv.URL, "dfs", times)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != times {
+		t.Fatalf("played %d mazes, want %d", len(stats), times)
+	}
+	total := 0
+	for i, s := range stats {
+		if !s.Solved {
+			t.Errorf("maze %d wasn't solved", i+1)
+		}
+		total += s.Steps
+	}
+	if results.Mazes != times {
+		t.Errorf("daedalus counted %d mazes, want %d", results.Mazes, times)
+	}
+	if results.AverageSteps != total/times {
+		t.Errorf("daedalus averaged %d steps, icarus took %d", results.AverageSteps, total/times)
+	}
+	if results.Score <= 0 {
+		t.Errorf("score of %d", results.Score)
+	}
+
+	select {
+	case <-shutdown:
+		t.Fatal("/done shut down the server mounting the routes")
+	default:
+	}
+	if _, _, err := PlayAgainst(srv.URL, "dfs", 1); err != nil {
+		t.Fatalf("playing again after /done: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/viper"
+)
+
+// With --output json the results daedalus, icarus, duel, bench and stats
+// end with are written to stdout as a single line of JSON each, instead of
+// the tables meant for people, and nothing else goes to stdout. Run
+// together daedalus and icarus write a line each.
+// --quiet leaves out what is printed and logged for every maze and move.
+
+// Checks the output and charset flags are ones we know
+func checkOutput() error {
+	if o := viper.GetString("output"); o != "text" && o != "json" {
+		return fmt.Errorf("unknown output %q, use text or json", o)
+	}
+	if c := viper.GetString("charset"); c != "ascii" && c != "unicode" {
+		return fmt.Errorf("unknown charset %q, use ascii or unicode", c)
+	}
+	if _, _, err := windowSize(viper.GetString("window")); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Returns the most rooms of a maze printed across and down the window flag
+// asks for, 0 for no limit
+func windowSize(window string) (width, height int, err error) {
+	if window == "" {
+		return 0, 0, nil
+	}
+	if _, err := fmt.Sscanf(window, "%dx%d", &width, &height); err != nil || width < 0 || height < 0 {
+		return 0, 0, fmt.Errorf("window has to be like 100x100, not %q", window)
+	}
+	return width, height, nil
+}
+
+// Whether the results are written as JSON
+func jsonOutput() bool {
+	return daedalusConf.Output == "json"
+}
+
+// Whether daedalus prints every maze he hands out
+func printsMazes() bool {
+	return !daedalusConf.Quiet && !jsonOutput()
+}
+
+// Draws the maze to w with the configured charset and, with --color, the
+// rooms of the path marked. Of mazes larger than the window only the part
+// around Icarus is drawn, so huge ones don't take forever and flood the
+// terminal.
+func printMaze(w io.Writer, m mazelib.MazeI, path []mazelib.Coordinate) error {
+	width, height := daedalusConf.WindowWidth, daedalusConf.WindowHeight
+	x, y := m.Icarus()
+	if win := mazelib.Clip(m, mazelib.Coordinate{X: x, Y: y}, width, height); !win.Whole() {
+		o := win.Origin
+		fmt.Fprintf(w, "rooms %d,%d to %d,%d of the %dx%d laybrinth\n", o.X, o.Y, o.X+win.Width()-1, o.Y+win.Height()-1, m.Width(), m.Height())
+		m, path = win, win.Local(path)
+	}
+
+	unicode := daedalusConf.Charset == "unicode"
+	switch {
+	case daedalusConf.Color && unicode:
+		return mazelib.FprintColorBoxMaze(w, m, path)
+	case daedalusConf.Color:
+		return mazelib.FprintColorMaze(w, m, path)
+	case unicode:
+		return mazelib.FprintBoxMaze(w, m)
+	}
+	return mazelib.FprintMaze(w, m)
+}
+
+// Writes v to stdout as a line of JSON
+func printJSON(v interface{}) {
+	if err := json.NewEncoder(os.Stdout).Encode(v); err != nil {
+		slog.Error("couldn't write the results", "err", err)
+	}
+}
+
+// summary is what printSummary prints
+type summary struct {
+	Mazes        int           `json:"mazes"`
+	AverageSteps int           `json:"average_steps"`
+	Score        mazelib.Score `json:"score"`
+	Stats        mazelib.Stats `json:"stats"`
+	// how long the mazes took on average, when scoring by time
+	AverageTime time.Duration `json:"average_time,omitempty"`
+}
+
+func summarize(scores []int, durations []time.Duration) summary {
+	s := summary{
+		Mazes:        len(scores),
+		AverageSteps: mazelib.AvgScores(scores),
+		Score:        scoring().Score(scores),
+		Stats:        mazelib.Statistics(scores, histogramBuckets),
+	}
+	if scoringByTime() && len(durations) > 0 {
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+		s.AverageTime = total / time.Duration(len(durations))
+	}
+	return s
+}
+
+// daedalusReport is what daedalus prints when he shuts down
+type daedalusReport struct {
+	summary
+	Algorithms []algorithmScore `json:"algorithms"`
+	// the summaries of every client, if the server requires api keys
+	Clients map[string]summary `json:"clients,omitempty"`
+}
+
+// icarusReport is what icarus prints once he is done
+type icarusReport struct {
+	Mazes []solveStats `json:"mazes"`
+}
+
+// duelReport is what a duel ends with, the reports of both
+type duelReport struct {
+	Icarus   icarusReport   `json:"icarus"`
+	Daedalus daedalusReport `json:"daedalus"`
+}
@@ -0,0 +1,223 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/solve"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// In play a human takes the place of Icarus. He sees nothing but what a
+// strategy is told: the walls of the rooms he has been in.
+
+// Defining the play command.
+// This will be called as 'laybrinth play'
+var playCmd = &cobra.Command{
+	Use:   "play",
+	Short: "Solve the laybrinth yourself",
+	Long: `Play lets you find the treasure yourself, walking with the arrow keys
+  (or wasd, or hjkl) through the laybrinths Daedalus hands out. q gives up.
+
+  All you get to see is Icarus's map of the rooms you have been in, drawn
+  the same way watch mode does, which is exactly what the strategies get to
+  work with. With --local the laybrinths are generated in this process
+  instead of by a running Daedalus.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RunPlay()
+	},
+}
+
+func init() {
+	playCmd.Flags().Bool("local", false, "generate the laybrinths in this process instead of asking daedalus for them")
+	RootCmd.AddCommand(playCmd)
+}
+
+// The keys moving Icarus, arrow keys come as escape sequences ending in A to D
+var keys = map[byte]mazelib.Direction{
+	'w': mazelib.N, 'k': mazelib.N, 'A': mazelib.N,
+	's': mazelib.S, 'j': mazelib.S, 'B': mazelib.S,
+	'a': mazelib.W, 'h': mazelib.W, 'D': mazelib.W,
+	'd': mazelib.E, 'l': mazelib.E, 'C': mazelib.E,
+}
+
+func RunPlay() {
+	// daedalus keeps the results apart from those of the strategies
+	viper.Set("strategy", "human")
+	loadConfig()
+
+	var sess labyrinth
+	var l *localGame
+	remote := &session{}
+	if viper.GetBool("local") {
+		settings := currentSettings()
+		if err := settings.validate(); err != nil {
+			daedalusLog.Error("can't create mazes", "err", err)
+			return
+		}
+		l = newLocalGame("play", "human", settings)
+		sess = l
+	} else {
+		if err := configureClient(); err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer remote.close()
+		sess = remote
+	}
+
+	restore, err := rawTerminal()
+	if err != nil {
+		fmt.Println("can't read the keys from the terminal:", err)
+		return
+	}
+	// put the terminal back the way it was if we get interrupted
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	go func() {
+		<-interrupted
+		restore()
+		os.Exit(1)
+	}()
+
+	in := bufio.NewReader(os.Stdin)
+	var stats []solveStats
+	for x := 0; x < viper.GetInt("times"); x++ {
+		s, err := playMaze(sess, in, fmt.Sprintf("laybrinth %d of %d", x+1, viper.GetInt("times")))
+		stats = append(stats, s)
+		if err != nil {
+			restore()
+			fmt.Println(err)
+			return
+		}
+	}
+	restore()
+
+	printSolveStats(stats)
+	if l != nil {
+		l.finish()
+		printSummary(l.game.scores, l.game.durations)
+		return
+	}
+	remote.done()
+}
+
+// Lets the human walk Icarus through a maze until he finds the treasure or
+// gives up. Returns an error if the connection to daedalus got lost.
+func playMaze(sess labyrinth, in *bufio.Reader, title string) (stats solveStats, err error) {
+	started := time.Now()
+	s, err := sess.Awake()
+	if err != nil {
+		return stats, err
+	}
+	defer func() { stats.Duration = time.Since(started) }()
+	m := solve.NewMap()
+	pos := mazelib.Coordinate{}
+	m.Record(pos, s)
+
+	status := "find the treasure"
+	for {
+		// move the cursor to the top left and clear the screen
+		fmt.Print("\033[H\033[2J")
+		fmt.Print(m.Render(pos))
+		fmt.Printf("%s, %d steps: %s\n", title, stats.Steps, status)
+
+		key, err := in.ReadByte()
+		if err != nil {
+			return stats, err
+		}
+		if key == 'q' {
+			return stats, nil
+		}
+		if key == 27 {
+			// skip the [ of an arrow key and use the letter ending it
+			in.ReadByte()
+			if key, err = in.ReadByte(); err != nil {
+				return stats, err
+			}
+		}
+		dir, ok := keys[key]
+		if !ok {
+			continue
+		}
+
+		surveys, err := sess.Walk([]mazelib.Direction{dir})
+		if _, lost := err.(*connectionError); lost {
+			return stats, err
+		}
+		status = "went " + dir.String()
+		if len(surveys) > 0 {
+			stats.Steps++
+			if m.Known(pos.Move(dir)) {
+				stats.Backtracks++
+			}
+			pos = pos.Move(dir)
+			m.Record(pos, surveys[0])
+		}
+		switch {
+		case errors.Is(err, mazelib.ErrVictory):
+			stats.Steps++
+			stats.Solved = true
+			return stats, pause(in, fmt.Sprintf("found the treasure in %d steps", stats.Steps))
+		case err != nil:
+			if e, ok := err.(*mazelib.ReplyError); ok {
+				switch e.Code {
+				case mazelib.ErrCodeNoActiveMaze, mazelib.ErrCodeStepLimit, mazelib.ErrCodeTimeLimit:
+					// daedalus won't let us go on in this maze
+					return stats, pause(in, e.Message)
+				}
+			}
+			stats.WallBumps++
+			m.AddWall(pos, dir)
+			status = "can't go " + dir.String() + ", there's a wall"
+		}
+	}
+}
+
+// Shows the message until a key is pressed
+func pause(in *bufio.Reader, message string) error {
+	fmt.Println(message + ", press any key to go on")
+	_, err := in.ReadByte()
+	return err
+}
+
+// Has the terminal hand over every key as soon as it's pressed, without
+// echoing it. Returns a function putting the terminal back the way it was.
+func rawTerminal() (func(), error) {
+	stty := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		return cmd.Output()
+	}
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() { stty(strings.TrimSpace(string(state))) }, nil
+}
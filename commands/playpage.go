@@ -0,0 +1,165 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import "net/http"
+
+// The page at /play lets a human solve laybrinths in the browser, with the
+// arrow keys or wasd. It speaks the same /awake and /move API icarus does
+// and only draws what those tell it, the rooms visited so far, so it doesn't
+// give anything away like the dashboard does.
+// With api keys configured the page asks for one, or takes it from ?key=.
+
+// The API response to the /play address
+func ShowPlayPage(c *Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(playPage))
+}
+
+const playPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Laybrinth</title>
+<style>
+  body { font-family: sans-serif; background: #222; color: #eee; }
+  canvas { background: #fff; }
+  button { margin-left: 1em; }
+</style>
+</head>
+<body>
+<h1>Laybrinth</h1>
+<p>Find the treasure with the arrow keys or wasd. <button id="new">new laybrinth</button></p>
+<p id="status">waking up</p>
+<canvas id="maze"></canvas>
+<script>
+var room = 30;
+var params = new URLSearchParams(location.search);
+var key = params.get("key") || "";
+var session = "";
+// the surveys of the rooms visited, by "x,y" relative to the one we woke up in
+var rooms, pos, steps, bumps, done = true;
+
+var moves = {
+  ArrowUp: "up", w: "up", ArrowDown: "down", s: "down",
+  ArrowLeft: "left", a: "left", ArrowRight: "right", d: "right"
+};
+var offsets = { up: [0, -1], down: [0, 1], left: [-1, 0], right: [1, 0] };
+var walls = { up: "top", down: "bottom", left: "left", right: "right" };
+
+function status(text) {
+  document.getElementById("status").textContent = text;
+}
+
+function call(path) {
+  var url = path + (session ? "?session=" + encodeURIComponent(session) : "");
+  return fetch(url, { headers: { "X-API-Key": key, "X-Strategy": "human", "Accept": "application/json" } })
+    .then(function(r) { return r.json(); });
+}
+
+function awake() {
+  call("/awake").then(function(rep) {
+    if (rep.error && rep.message == "unknown api key") {
+      key = prompt("Daedalus wants an api key") || "";
+      return awake();
+    }
+    if (rep.error) { status(rep.message); return; }
+    session = rep.session || "";
+    rooms = { "0,0": rep.survey };
+    pos = [0, 0];
+    steps = 0;
+    bumps = 0;
+    done = false;
+    draw();
+    status("find the treasure");
+  }).catch(function(e) { status("can't reach daedalus: " + e); });
+}
+
+function move(dir) {
+  if (done) { return; }
+  call("/move/" + dir).then(function(rep) {
+    if (rep.victory) {
+      steps++;
+      done = true;
+      status("found the treasure in " + steps + " steps" + (bumps ? ", bumping into " + bumps + " walls" : ""));
+      return;
+    }
+    if (rep.error) {
+      if (rep.error_code == "WALL_HIT" || rep.error_code == "OUT_OF_BOUNDS") {
+        bumps++;
+        rooms[pos][walls[dir]] = true;
+        draw();
+        status("can't go " + dir + ", " + steps + " steps");
+      } else {
+        done = rep.error_code != "INVALID_DIRECTION";
+        status(rep.message);
+      }
+      return;
+    }
+    steps++;
+    pos = [pos[0] + offsets[dir][0], pos[1] + offsets[dir][1]];
+    rooms[pos] = rep.survey;
+    draw();
+    status(steps + " steps");
+  }).catch(function(e) { status("can't reach daedalus: " + e); });
+}
+
+function draw() {
+  var minX = 0, maxX = 0, minY = 0, maxY = 0;
+  Object.keys(rooms).forEach(function(k) {
+    var c = k.split(",").map(Number);
+    minX = Math.min(minX, c[0]); maxX = Math.max(maxX, c[0]);
+    minY = Math.min(minY, c[1]); maxY = Math.max(maxY, c[1]);
+  });
+  var canvas = document.getElementById("maze");
+  var ctx = canvas.getContext("2d");
+  canvas.width = (maxX - minX + 1) * room + 2;
+  canvas.height = (maxY - minY + 1) * room + 2;
+  ctx.translate(1 - minX * room, 1 - minY * room);
+
+  ctx.fillStyle = "#8cf";
+  ctx.fillRect(0, 0, room, room);
+
+  ctx.strokeStyle = "#000";
+  ctx.lineWidth = 2;
+  ctx.beginPath();
+  Object.keys(rooms).forEach(function(k) {
+    var c = k.split(",").map(Number), w = rooms[k];
+    var l = c[0] * room, t = c[1] * room;
+    ctx.fillStyle = "#eee";
+    if (k != "0,0") { ctx.fillRect(l, t, room, room); }
+    if (w.top) { ctx.moveTo(l, t); ctx.lineTo(l + room, t); }
+    if (w.bottom) { ctx.moveTo(l, t + room); ctx.lineTo(l + room, t + room); }
+    if (w.left) { ctx.moveTo(l, t); ctx.lineTo(l, t + room); }
+    if (w.right) { ctx.moveTo(l + room, t); ctx.lineTo(l + room, t + room); }
+  });
+  ctx.stroke();
+
+  ctx.fillStyle = "#d00";
+  ctx.beginPath();
+  ctx.arc(pos[0] * room + room / 2, pos[1] * room + room / 2, room / 3, 0, 2 * Math.PI);
+  ctx.fill();
+}
+
+document.addEventListener("keydown", function(e) {
+  var dir = moves[e.key];
+  if (dir) { e.preventDefault(); move(dir); }
+});
+document.getElementById("new").addEventListener("click", awake);
+awake();
+</script>
+</body>
+</html>
+`
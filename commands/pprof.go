@@ -0,0 +1,46 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// Profiles of a running daedalus or icarus, for finding the hot spots of
+// generators and solvers during long benchmark runs. They are served at
+// /debug/pprof/, so `go tool pprof` finds them where it expects.
+
+// Returns a handler serving the profiles
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// Serves the profiles of icarus on their own address, as he has no server
+// to add them to
+func servePprof(addr string) {
+	go func() {
+		if err := http.ListenAndServe(addr, pprofHandler()); err != nil {
+			icarusLog.Error("couldn't serve the profiles", "err", err)
+		}
+	}()
+}
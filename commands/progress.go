@@ -0,0 +1,63 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"sync"
+	"time"
+)
+
+// progress logs how far icarus got every once in a while when solving a lot
+// of laybrinths, with the average steps so far and when he'll be done.
+type progress struct {
+	sync.Mutex
+	total  int
+	done   int
+	solved int
+	steps  int
+	begun  time.Time
+	// when progress was logged last, and how often it is
+	logged time.Time
+	every  time.Duration
+}
+
+// Starts tracking the progress of solving total mazes, logging it every so often.
+// Never logs if every isn't positive.
+func newProgress(total int, every time.Duration) *progress {
+	now := time.Now()
+	return &progress{total: total, begun: now, logged: now, every: every}
+}
+
+// Counts a maze as done and logs the progress if it is time to
+func (p *progress) add(s solveStats) {
+	p.Lock()
+	defer p.Unlock()
+	p.done++
+	p.steps += s.Steps
+	if s.Solved {
+		p.solved++
+	}
+	if p.every <= 0 || p.done == p.total || time.Since(p.logged) < p.every {
+		return
+	}
+	p.logged = time.Now()
+
+	elapsed := time.Since(p.begun)
+	eta := elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)
+	icarusLog.Info("progress",
+		"maze", p.done, "mazes", p.total, "solved", p.solved,
+		"avg_steps", p.steps/p.done, "elapsed", elapsed.Round(time.Second), "eta", eta.Round(time.Second))
+}
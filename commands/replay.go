@@ -0,0 +1,200 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// The traces daedalus writes to trace-dir double as replays: they hold the
+// whole maze and every move Icarus tried in it, with the time he tried it.
+// The replay command plays one back in the terminal, move by move.
+
+// Defining the replay command.
+// This will be called as 'laybrinth replay <trace file>'
+var replayCmd = &cobra.Command{
+	Use:   "replay <trace file>",
+	Short: "Replay a laybrinth from its trace",
+	Long: `Replay draws the laybrinth of a trace written by Daedalus to trace-dir
+  and walks Icarus through it the way he did, pausing watch-delay after every
+  move. The rooms he has been to are marked with a dot, or in blue with
+  --color. With --out the replay is written to a file as an animated GIF
+  instead, drawn the way visualize draws it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+		if err := replay(args[0], viper.GetString("out")); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	replayCmd.Flags().StringP("out", "o", "", "file the replay is written to as an animated GIF (default is to replay it in the terminal)")
+	replayCmd.Flags().Int("room-size", 16, "size of a room in the GIF, in pixels, at least 2")
+	replayCmd.Flags().StringSlice("colors", nil, "colors the GIF is drawn in, like wall=#000000, see visualize")
+	RootCmd.AddCommand(replayCmd)
+}
+
+// Loads the trace in the file at path.
+// The mazes written by generate load as traces without any moves.
+func loadTrace(path string) (mazeTrace, error) {
+	t, err := readTrace(path)
+	if err != nil {
+		return t, err
+	}
+	if err := t.layout().Whole(); err != nil {
+		return t, fmt.Errorf("%s %v", path, err)
+	}
+	if err := t.layout().Placed(); err != nil {
+		return t, fmt.Errorf("%s %v", path, err)
+	}
+	return t, nil
+}
+
+// Reads the trace in the file at path, without checking the maze makes sense.
+// Mazes in .txt files are read as drawn by PrintMaze, as traces without moves.
+func readTrace(path string) (mazeTrace, error) {
+	var t mazeTrace
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return t, err
+	}
+	if strings.HasSuffix(path, ".txt") {
+		l, err := mazelib.ParseMaze(bytes.NewReader(b))
+		if err != nil {
+			return t, fmt.Errorf("%s isn't a maze: %v", path, err)
+		}
+		t.Width, t.Height, t.Walls, t.Start, t.Treasure = l.Width, l.Height, l.Walls, l.Start, l.Treasure
+		return t, nil
+	}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return t, fmt.Errorf("%s isn't a trace: %v", path, err)
+	}
+	return t, nil
+}
+
+// Plays back the trace in the file at path, or writes it to out as a GIF
+func replay(path, out string) error {
+	t, err := loadTrace(path)
+	if err != nil {
+		return err
+	}
+	if out != "" {
+		opts, walk, err := renderOptions(nil)
+		if err != nil {
+			return err
+		}
+		b, err := t.animate(opts, walk)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(out, b, 0644)
+	}
+
+	visited := map[mazelib.Coordinate]bool{t.Start: true}
+	walked := []mazelib.Coordinate{t.Start}
+	maze := t.maze()
+	frame := func(pos mazelib.Coordinate, status string) {
+		// move the cursor to the top left and clear the screen
+		fmt.Print("\033[H\033[2J")
+		if viper.GetBool("color") {
+			maze.icarus = pos
+			if err := printMaze(os.Stdout, maze, walked); err != nil {
+				fmt.Println(err)
+			}
+		} else {
+			fmt.Print(t.render(pos, visited))
+		}
+		fmt.Println(status)
+		time.Sleep(viper.GetDuration("watch-delay"))
+	}
+
+	frame(t.Start, fmt.Sprintf("maze %d of session %s, generated by %s with seed %d", t.Maze, t.Session, t.Algorithm, t.Seed))
+	var last time.Time
+	steps := 0
+	for i, m := range t.Moves {
+		if m.ErrorCode == "" {
+			steps++
+		}
+		visited[m.Icarus] = true
+		walked = append(walked, m.Icarus)
+		elapsed := time.Duration(0)
+		if i > 0 {
+			elapsed = m.Time.Sub(last)
+		}
+		last = m.Time
+
+		status := fmt.Sprintf("move %d of %d: %s, %d steps (+%s)", i+1, len(t.Moves), m.Direction, steps, elapsed)
+		switch {
+		case m.Victory:
+			status += ", found the treasure"
+		case m.ErrorCode != "":
+			status += ", refused: " + m.ErrorCode
+		}
+		frame(m.Icarus, status)
+	}
+	return nil
+}
+
+// Draws the whole maze the way PrintMaze does, with Icarus at pos as @, the
+// start as ⏀, the treasure as ⏃ and the other rooms he has visited as a dot
+func (v mazeView) render(pos mazelib.Coordinate, visited map[mazelib.Coordinate]bool) string {
+	var b strings.Builder
+	b.WriteString("_" + strings.Repeat("___", v.Width) + "\n")
+	for y := 0; y < v.Height; y++ {
+		b.WriteString("|")
+		for x := 0; x < v.Width; x++ {
+			c := mazelib.Coordinate{X: x, Y: y}
+			s := v.Walls[y][x]
+			floor := " "
+			if s.Bottom {
+				floor = "_"
+			}
+			mark := floor
+			switch {
+			case c == pos:
+				mark = "@"
+			case c == v.Treasure:
+				mark = "⏃"
+			case c == v.Start:
+				mark = "⏀"
+			case visited[c]:
+				mark = "."
+			}
+			b.WriteString(mark + floor)
+			if s.Right {
+				b.WriteString("|")
+			} else {
+				b.WriteString(floor)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
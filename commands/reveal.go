@@ -0,0 +1,89 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net/http"
+	"strconv"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/metrics"
+)
+
+// reveal shows a solved maze as it really is, along with the shortest way
+// from where Icarus awoke to the treasure and how hard the maze was
+type reveal struct {
+	mazeView
+	Optimal      []mazelib.Coordinate `json:"optimal"`
+	OptimalSteps int                  `json:"optimal_steps"`
+	Metrics      metrics.Metrics      `json:"metrics"`
+}
+
+// The API response to the /reveal address.
+// The maze is only given away once Icarus solved it, until he awakes in the next one.
+func RevealMaze(c *Context) {
+	g, ok := findGame(c)
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session, call /awake first", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	sendReveal(c, g)
+}
+
+// The API response to GET /v2/sessions/:session/mazes/:maze/reveal
+func RevealMazeV2(c *Context) {
+	g, ok := findGameByID(c, c.Param("session"))
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+
+	id, err := strconv.Atoi(c.Param("maze"))
+	if err != nil || id < 1 || id > g.mazeID {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown maze", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+	if id != g.mazeID {
+		respond(c, http.StatusGone, mazelib.Reply{Error: true, Message: "only the last maze of a session can be revealed", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+	sendReveal(c, g)
+}
+
+func sendReveal(c *Context, g *game) {
+	if g.maze == nil || !g.maze.solved {
+		respond(c, http.StatusForbidden, mazelib.Reply{Error: true, Message: "the maze is only revealed once it is solved"})
+		return
+	}
+
+	optimal := g.maze.shortestPath(g.maze.start, g.maze.end)
+	respond(c, http.StatusOK, reveal{
+		mazeView:     g.view(),
+		Optimal:      optimal,
+		OptimalSteps: len(optimal) - 1,
+		Metrics:      metrics.Measure(g.maze),
+	})
+}
+
+// Returns the shortest way through the maze between the rooms, both included.
+// Mazes with loops may have several, this is one of them.
+func (m *Maze) shortestPath(from, to mazelib.Coordinate) []mazelib.Coordinate {
+	return mazelib.PathTo(m, mazelib.DistanceMap(m, from), to)
+}
@@ -0,0 +1,51 @@
+//go:build !nogin
+
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.ReleaseMode)
+
+	routers["gin"] = newGin
+}
+
+// Serves the routes with gin
+func newGin(routes []route, logRequests bool) http.Handler {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	if logRequests {
+		r.Use(gin.Logger())
+	}
+	for _, rt := range routes {
+		handlers := rt.handlers
+		h := func(gc *gin.Context) {
+			newContext(gc.Writer, gc.Request, gc.Param).run(handlers)
+		}
+		if rt.method == "" {
+			r.Any(rt.path, h)
+		} else {
+			r.Handle(rt.method, rt.path, h)
+		}
+	}
+	return r
+}
@@ -0,0 +1,117 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Serves the routes with net/http's ServeMux, which spares daedalus the
+// dependency on gin and some of the work per request
+func newMux(routes []route, logRequests bool) http.Handler {
+	mux := http.NewServeMux()
+	mountRoutes(mux, routes)
+	return &muxHandler{mux: mux, logRequests: logRequests}
+}
+
+// Adds the routes to the mux
+func mountRoutes(mux *http.ServeMux, routes []route) {
+	for _, rt := range routes {
+		pattern := muxPattern(rt.path)
+		if rt.method != "" {
+			pattern = rt.method + " " + pattern
+		}
+		handlers := rt.handlers
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			newContext(w, r, r.PathValue).run(handlers)
+		})
+	}
+}
+
+// Turns the :names and *names of a route into the wildcards of ServeMux
+func muxPattern(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, ":"):
+			parts[i] = "{" + part[1:] + "}"
+		case strings.HasPrefix(part, "*"):
+			parts[i] = "{" + part[1:] + "...}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// muxHandler does for requests to the mux what gin's Logger and Recovery do
+type muxHandler struct {
+	mux         http.Handler
+	logRequests bool
+}
+
+func (h *muxHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	begun := time.Now()
+	sw := &statusWriter{ResponseWriter: w}
+	defer func() {
+		if err := recover(); err != nil {
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+			daedalusLog.Error("handler panicked", "method", r.Method, "path", r.URL.Path, "err", err)
+			if sw.status == 0 {
+				sw.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+		if h.logRequests {
+			daedalusLog.Info("request", "method", r.Method, "path", r.URL.Path, "status", sw.status, "took", time.Since(begun))
+		}
+	}()
+	h.mux.ServeHTTP(sw, r)
+}
+
+// statusWriter remembers the status of the reply to log it
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Lets http.ResponseController flush the reply
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Lets the websocket upgrader take over the connection
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.status = http.StatusSwitchingProtocols
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
@@ -0,0 +1,104 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// route is an address daedalus answers
+type route struct {
+	// "" answers every method
+	method string
+	// :name matches a part of the path and *name the rest of it
+	path string
+	// the middleware of the route's groups and then its handler
+	handlers []HandlerFunc
+}
+
+// routeGroup adds routes below a prefix, which have to get through the
+// middleware of the group first
+type routeGroup struct {
+	prefix     string
+	middleware []HandlerFunc
+	routes     *[]route
+}
+
+// Returns a group below this one, with middleware of its own
+func (g *routeGroup) Group(prefix string, middleware ...HandlerFunc) *routeGroup {
+	return &routeGroup{
+		prefix:     joinPath(g.prefix, prefix),
+		middleware: append(append([]HandlerFunc{}, g.middleware...), middleware...),
+		routes:     g.routes,
+	}
+}
+
+func (g *routeGroup) handle(method, p string, h HandlerFunc) {
+	handlers := append(append([]HandlerFunc{}, g.middleware...), h)
+	*g.routes = append(*g.routes, route{method: method, path: joinPath(g.prefix, p), handlers: handlers})
+}
+
+func (g *routeGroup) GET(p string, h HandlerFunc)    { g.handle(http.MethodGet, p, h) }
+func (g *routeGroup) POST(p string, h HandlerFunc)   { g.handle(http.MethodPost, p, h) }
+func (g *routeGroup) PUT(p string, h HandlerFunc)    { g.handle(http.MethodPut, p, h) }
+func (g *routeGroup) DELETE(p string, h HandlerFunc) { g.handle(http.MethodDelete, p, h) }
+func (g *routeGroup) Any(p string, h HandlerFunc)    { g.handle("", p, h) }
+
+func joinPath(prefix, p string) string {
+	if p == "" {
+		return prefix
+	}
+	return path.Join("/", prefix, p)
+}
+
+// Answers requests with an http.Handler
+func wrapH(h http.Handler) HandlerFunc {
+	return func(c *Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// The routers daedalus can serve his routes with, by name.
+// gin is left out of builds with the nogin tag, which don't depend on it.
+var routers = map[string]func(routes []route, logRequests bool) http.Handler{
+	"mux": newMux,
+}
+
+// Returns the handler serving the routes of daedalus with the router of the
+// name, logging every request if asked to. Without a name gin is used if
+// it is built in, net/http's ServeMux otherwise.
+func newRouter(name string, logRequests bool) (http.Handler, error) {
+	if name == "" {
+		name = "mux"
+		if _, ok := routers["gin"]; ok {
+			name = "gin"
+		}
+	}
+	newHandler, ok := routers[name]
+	if !ok {
+		var names []string
+		for n := range routers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown router %q, use %s", name, strings.Join(names, " or "))
+	}
+	return newHandler(daedalusRoutes(), logRequests), nil
+}
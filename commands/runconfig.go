@@ -0,0 +1,142 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// The settings looked at on every move and request are read from viper once
+// when a command starts, by loadConfig, instead of every time they're needed.
+
+// daedalusConfig is what daedalus needs to know while mazes are played
+type daedalusConfig struct {
+	MaxSteps    int
+	StepLimit   int
+	TimeLimit   time.Duration
+	WallPenalty int
+	Color       bool
+	TraceDir    string
+	KeepTraces  int
+	APIKeys     []string
+	// the bands of large mazes carved at the same time
+	ParallelGeneration int
+	// how mazes are printed, and if they are: the most rooms across and
+	// down, 0 for no limit, and the charset, quiet and output flags
+	WindowWidth, WindowHeight int
+	Charset                   string
+	Quiet                     bool
+	Output                    string
+}
+
+// icarusConfig is what icarus needs to know for every request he sends
+type icarusConfig struct {
+	// the url daedalus is reached at, and his Unix socket if he listens on one
+	Server string
+	Socket string
+	// the api key he authenticates with and the strategy he plays
+	APIKey   string
+	Strategy string
+	// the rooms of the mazes he asks for, to count those left unexplored
+	Rooms     int
+	Transport string
+	Encoding  string
+	Batch     bool
+	Retries   int
+	// where daedalus serves gRPC, for the grpc transport
+	GRPCAddr string
+	// sent along with every request, built of the above
+	Header http.Header
+}
+
+var (
+	daedalusConf daedalusConfig
+	icarusConf   icarusConfig
+)
+
+// Reads the settings of daedalus and icarus from viper.
+// Has to be called again by commands changing them afterwards.
+func loadConfig() {
+	daedalusConf = daedalusConfig{
+		MaxSteps:    viper.GetInt("max-steps"),
+		StepLimit:   viper.GetInt("step-limit"),
+		TimeLimit:   viper.GetDuration("time-limit"),
+		WallPenalty: viper.GetInt("wall-penalty"),
+		Color:       viper.GetBool("color"),
+		TraceDir:    viper.GetString("trace-dir"),
+		KeepTraces:  viper.GetInt("keep-traces"),
+		APIKeys:     viper.GetStringSlice("api-keys"),
+
+		ParallelGeneration: viper.GetInt("parallel-generation"),
+
+		Charset: viper.GetString("charset"),
+		Quiet:   viper.GetBool("quiet"),
+		Output:  viper.GetString("output"),
+	}
+	// checkOutput tells about a window that isn't one
+	daedalusConf.WindowWidth, daedalusConf.WindowHeight, _ = windowSize(viper.GetString("window"))
+
+	icarusConf = icarusConfig{
+		APIKey:    viper.GetString("api-key"),
+		Strategy:  viper.GetString("strategy"),
+		Rooms:     viper.GetInt("width") * viper.GetInt("height"),
+		Transport: viper.GetString("transport"),
+		Encoding:  viper.GetString("encoding"),
+		Batch:     viper.GetBool("batch"),
+		Retries:   viper.GetInt("retries"),
+	}
+	// like a server without a host, daedalus is expected on the local
+	// machine if he serves gRPC on all interfaces
+	icarusConf.GRPCAddr = viper.GetString("grpc-addr")
+	if host, port, err := net.SplitHostPort(icarusConf.GRPCAddr); err == nil && host == "" {
+		icarusConf.GRPCAddr = "127.0.0.1:" + port
+	}
+	icarusConf.Header = icarusHeader()
+
+	// Without a server to connect to, icarus uses the socket daedalus
+	// listens on or else expects him on the local machine.
+	server := viper.GetString("server")
+	if server == "" {
+		server = viper.GetString("addr")
+	}
+	if path := strings.TrimPrefix(server, "unix:"); path != server {
+		icarusConf.Socket = path
+		// the host doesn't matter, dial connects to the socket anyway
+		icarusConf.Server = "http://daedalus"
+		return
+	}
+	server = viper.GetString("server")
+	if server == "" {
+		server = "http://127.0.0.1:" + viper.GetString("port")
+		if _, port, err := net.SplitHostPort(viper.GetString("addr")); err == nil {
+			server = "http://127.0.0.1:" + port
+		}
+	}
+	icarusConf.Server = strings.TrimSuffix(server, "/")
+}
+
+// Returns the header icarus sends along with every request, built of
+// icarusConf
+func icarusHeader() http.Header {
+	h := requestHeader()
+	h.Set("Accept", encoding())
+	return h
+}
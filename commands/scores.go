@@ -0,0 +1,114 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net/http"
+	"sort"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// how many runs the leaderboard lists as the best ones
+const bestRuns = 10
+
+// leaderboard ranks the clients by their score
+type leaderboard struct {
+	Clients []clientScore `json:"clients"`
+	// the solved mazes with the fewest steps
+	Best []result `json:"best"`
+}
+
+// clientScore sums up the results of a single client
+type clientScore struct {
+	Client       string `json:"client"`
+	Mazes        int    `json:"mazes"`
+	Solved       int    `json:"solved"`
+	AverageSteps int    `json:"average_steps"`
+	BestSteps    int    `json:"best_steps"`
+	// the score by the rules of the challenge
+	Score mazelib.Score `json:"score"`
+}
+
+// The API response to the /scores address.
+// Without a session it ranks all results in the store, with one only those
+// of the session.
+func GetScores(c *Context) {
+	results := store.all()
+	if id := c.Query("session"); id != "" {
+		g, ok := findGameByID(c, id)
+		if !ok {
+			respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+			return
+		}
+		var own []result
+		for _, r := range results {
+			if r.Session == g.id {
+				own = append(own, r)
+			}
+		}
+		results = own
+	}
+
+	respond(c, http.StatusOK, rank(results))
+}
+
+// Builds the leaderboard of the given results
+func rank(results []result) leaderboard {
+	byClient := map[string][]result{}
+	for _, r := range results {
+		byClient[r.Client] = append(byClient[r.Client], r)
+	}
+
+	lb := leaderboard{Clients: []clientScore{}, Best: []result{}}
+	for client, rs := range byClient {
+		cs := clientScore{Client: client, Mazes: len(rs)}
+		var steps []int
+		for _, r := range rs {
+			steps = append(steps, r.Steps)
+			if !r.Solved {
+				continue
+			}
+			cs.Solved++
+			if cs.BestSteps == 0 || r.Steps < cs.BestSteps {
+				cs.BestSteps = r.Steps
+			}
+		}
+		cs.AverageSteps = mazelib.AvgScores(steps)
+		cs.Score = scoring().Score(steps)
+		lb.Clients = append(lb.Clients, cs)
+	}
+	sort.Slice(lb.Clients, func(i, j int) bool {
+		if lb.Clients[i].Score.Final != lb.Clients[j].Score.Final {
+			return lb.Clients[i].Score.Final < lb.Clients[j].Score.Final
+		}
+		if lb.Clients[i].AverageSteps != lb.Clients[j].AverageSteps {
+			return lb.Clients[i].AverageSteps < lb.Clients[j].AverageSteps
+		}
+		return lb.Clients[i].Client < lb.Clients[j].Client
+	})
+
+	for _, r := range results {
+		if r.Solved {
+			lb.Best = append(lb.Best, r)
+		}
+	}
+	sort.SliceStable(lb.Best, func(i, j int) bool { return lb.Best[i].Steps < lb.Best[j].Steps })
+	if len(lb.Best) > bestRuns {
+		lb.Best = lb.Best[:bestRuns]
+	}
+	return lb
+}
@@ -0,0 +1,135 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/gen"
+	"github.com/spf13/viper"
+)
+
+// mazeSettings are what new mazes are created with
+type mazeSettings struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// one of the generators, or random
+	Algorithm string `json:"algorithm"`
+	// how far from the treasure Icarus awakes, easy, normal, hard or extreme
+	Difficulty string `json:"difficulty"`
+	// makes every maze the same, 0 for a new one every time
+	Seed int64 `json:"seed,omitempty"`
+}
+
+var settingsMu sync.Mutex
+
+// the settings changed by an admin, until then the flags are used
+var changedSettings *mazeSettings
+
+// Returns the settings new mazes are created with
+func currentSettings() mazeSettings {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	if changedSettings != nil {
+		return *changedSettings
+	}
+	return mazeSettings{
+		Width:      viper.GetInt("width"),
+		Height:     viper.GetInt("height"),
+		Algorithm:  viper.GetString("algorithm"),
+		Difficulty: viper.GetString("difficulty"),
+	}
+}
+
+// Checks that mazes can be created with the settings
+func (s mazeSettings) validate() error {
+	if s.Width < 3 || s.Height < 3 {
+		return fmt.Errorf("a laybrinth has to be at least 3x3 rooms, not %dx%d", s.Width, s.Height)
+	}
+	if _, ok := gen.Generators[s.Algorithm]; !ok && s.Algorithm != "random" {
+		return fmt.Errorf("unknown algorithm %q", s.Algorithm)
+	}
+	switch s.Difficulty {
+	case "easy", "normal", "hard", "extreme":
+		return nil
+	}
+	return fmt.Errorf("unknown difficulty %q, use easy, normal, hard or extreme", s.Difficulty)
+}
+
+// Tells whether Icarus may awake at start, as far from the treasure as he is.
+// Easy keeps him close to the treasure, hard at least half the laybrinth
+// away and normal only keeps him off the treasure's diagonal. Extreme mazes
+// aren't placed this way, they have the start and the treasure at the ends
+// of their longest path.
+func (s mazeSettings) startFits(start, treasure mazelib.Coordinate) bool {
+	if start.X-treasure.X+start.Y-treasure.Y == 0 {
+		return false
+	}
+	distance := start.ManhattanDist(treasure)
+	switch s.Difficulty {
+	case "easy":
+		return distance <= (s.Width+s.Height)/4 || distance == 1
+	case "hard":
+		// Icarus and the treasure are never placed in the last row or column,
+		// which keeps them from being further apart in small laybrinths
+		return distance >= (s.Width+s.Height)/2 || distance >= s.Width+s.Height-4
+	}
+	return true
+}
+
+// Returns the settings a request to /awake asks for.
+// The size, algorithm and seed of the maze can be set with query parameters
+// of the same name, as long as the size stays within max-width and max-height.
+func requestedSettings(c *Context) (mazeSettings, error) {
+	s := currentSettings()
+	params := []struct {
+		name string
+		val  *int
+		max  int
+	}{
+		{"width", &s.Width, viper.GetInt("max-width")},
+		{"height", &s.Height, viper.GetInt("max-height")},
+	}
+	for _, p := range params {
+		q := c.Query(p.name)
+		if q == "" {
+			continue
+		}
+		v, err := strconv.Atoi(q)
+		if err != nil {
+			return s, fmt.Errorf("%s has to be a number, not %q", p.name, q)
+		}
+		if v > p.max {
+			return s, fmt.Errorf("%s can't be more than %d", p.name, p.max)
+		}
+		*p.val = v
+	}
+
+	if q := c.Query("algorithm"); q != "" {
+		s.Algorithm = q
+	}
+	if q := c.Query("seed"); q != "" {
+		seed, err := strconv.ParseInt(q, 10, 64)
+		if err != nil {
+			return s, fmt.Errorf("seed has to be a number, not %q", q)
+		}
+		s.Seed = seed
+	}
+	return s, s.validate()
+}
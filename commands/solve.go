@@ -0,0 +1,91 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the solve command.
+// This will be called as 'laybrinth solve <maze file>'
+var solveCmd = &cobra.Command{
+	Use:   "solve <maze file>",
+	Short: "Let Icarus solve a laybrinth from a file",
+	Long: `Solve lets Icarus solve the laybrinth in a file written by generate, or
+  the one of a trace, or one drawn by hand in a .txt file the way generate
+  prints laybrinths, as many times as asked for with the strategy asked for.
+  Everything happens in this process, no server is started. Afterwards the
+  steps he took are compared with the shortest way to the treasure.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+		if err := solveFile(args[0]); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(solveCmd)
+}
+
+// Solves the maze in the file
+func solveFile(file string) error {
+	t, err := loadTrace(file)
+	if err != nil {
+		return err
+	}
+	if _, err := newStrategy(viper.GetString("strategy"), nil); err != nil {
+		return err
+	}
+	path := t.maze().shortestPath(t.Start, t.Treasure)
+	if path == nil {
+		return fmt.Errorf("the treasure can't be reached in %s", file)
+	}
+	l := newLocalGame("solve", viper.GetString("strategy"), mazeSettings{})
+	l.maze = &t.mazeView
+	var stats []solveStats
+	for x := 0; x < viper.GetInt("times"); x++ {
+		strat, _ := newStrategy(viper.GetString("strategy"), nil)
+		s, _ := solveMaze(l, strat, t.Width*t.Height)
+		stats = append(stats, s)
+	}
+	l.finish()
+	printSolveStats(stats)
+	saveLearned()
+
+	optimal := len(path) - 1
+	solved, steps := 0, 0
+	for _, s := range stats {
+		if s.Solved {
+			solved++
+			steps += s.Steps
+		}
+	}
+	if solved == 0 {
+		fmt.Printf("The shortest way to the treasure takes %d steps, Icarus didn't find it\n", optimal)
+		return nil
+	}
+	avg := steps / solved
+	fmt.Printf("The shortest way to the treasure takes %d steps, Icarus took %d on average (%.1fx)\n",
+		optimal, avg, float64(avg)/float64(optimal))
+	return nil
+}
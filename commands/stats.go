@@ -0,0 +1,108 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/viper"
+)
+
+// when RunServer started the server
+var started = time.Now()
+
+// stats describes what is going on on the server right now
+type stats struct {
+	Uptime string `json:"uptime"`
+	Games  int    `json:"games"`
+	// mazes handed out, including the ones being solved right now
+	MazesServed   int `json:"mazes_served"`
+	MazesFinished int `json:"mazes_finished"`
+	AverageSteps  int `json:"average_steps"`
+	MedianSteps   int `json:"median_steps"`
+	// more about how the steps of the mazes finished are distributed
+	Distribution mazelib.Stats `json:"distribution"`
+	// the number of the maze the session, or the game started last, is in
+	CurrentMaze int `json:"current_maze"`
+	// how the algorithms and strategies did against each other, over all results stored
+	Ratings ratings `json:"ratings"`
+	// the mazes finished, by the algorithm that generated them
+	Algorithms []algorithmScore `json:"algorithms"`
+}
+
+// algorithmScore sums up the mazes of a single algorithm
+type algorithmScore struct {
+	Algorithm    string `json:"algorithm"`
+	Mazes        int    `json:"mazes"`
+	Solved       int    `json:"solved"`
+	AverageSteps int    `json:"average_steps"`
+	Score        int    `json:"score"`
+}
+
+// Breaks the results down by algorithm, sorted by its name
+func byAlgorithm(results []result) []algorithmScore {
+	steps := map[string][]int{}
+	solved := map[string]int{}
+	for _, r := range results {
+		steps[r.Algorithm] = append(steps[r.Algorithm], r.Steps)
+		if r.Solved {
+			solved[r.Algorithm]++
+		}
+	}
+
+	as := []algorithmScore{}
+	for alg, s := range steps {
+		as = append(as, algorithmScore{
+			Algorithm:    alg,
+			Mazes:        len(s),
+			Solved:       solved[alg],
+			AverageSteps: mazelib.AvgScores(s),
+			Score:        mazelib.Scoring{MaxSteps: viper.GetInt("max-steps")}.Score(s).Final,
+		})
+	}
+	sort.Slice(as, func(i, j int) bool { return as[i].Algorithm < as[j].Algorithm })
+	return as
+}
+
+// The API response to the /stats address
+func GetStats(c *Context) {
+	scores := games.scores()
+	s := stats{
+		Uptime:        time.Since(started).Round(time.Second).String(),
+		Games:         games.count(),
+		MazesServed:   games.mazesServed(),
+		MazesFinished: len(scores),
+		AverageSteps:  mazelib.AvgScores(scores),
+		MedianSteps:   mazelib.MedianScores(scores),
+		Distribution:  mazelib.Statistics(scores, histogramBuckets),
+		Ratings:       rate(store.all()),
+		Algorithms:    byAlgorithm(store.since(started)),
+	}
+
+	if g, ok := findGame(c); ok {
+		g.Lock()
+		s.CurrentMaze = g.mazeID
+		g.Unlock()
+	} else if c.Query("session") != "" {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+
+	respond(c, http.StatusOK, s)
+}
@@ -0,0 +1,140 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// result is the outcome of a single maze played on daedalus
+type result struct {
+	Time time.Time `json:"time"`
+	// the algorithm that generated the maze, and the seed it was given
+	Algorithm string `json:"algorithm"`
+	Seed      int64  `json:"seed"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Steps     int    `json:"steps"`
+	Solved    bool   `json:"solved"`
+	// moves refused because of a wall or the edge of the maze
+	WallBumps int `json:"wall_bumps,omitempty"`
+	// whether the maze was retired because Icarus ran out of time
+	TimedOut bool `json:"timed_out,omitempty"`
+	// how long Icarus spent in the maze
+	Duration time.Duration `json:"duration"`
+	Strategy string        `json:"strategy,omitempty"`
+	Client   string        `json:"client,omitempty"`
+	Session  string        `json:"session"`
+}
+
+// scoreStore keeps the results of every maze played.
+// With a file configured the results are appended to it, one JSON object
+// per line, so they survive restarts of daedalus and can be analyzed later.
+type scoreStore struct {
+	sync.Mutex
+	path    string
+	results []result
+}
+
+// the results of this server, opened by RunServer
+var store = &scoreStore{}
+
+// Opens the store kept in the file at path, reading the results already in it.
+// An empty path makes a store which only lives in memory.
+func openStore(path string) (*scoreStore, error) {
+	s := &scoreStore{path: path}
+	if path == "" {
+		return s, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r result
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, err
+		}
+		s.results = append(s.results, r)
+	}
+	return s, scanner.Err()
+}
+
+// Adds a result to the store and writes it to the file
+func (s *scoreStore) add(r result) error {
+	s.Lock()
+	defer s.Unlock()
+	s.results = append(s.results, r)
+	if s.path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	err = json.NewEncoder(f).Encode(r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Returns all results in the store
+func (s *scoreStore) all() []result {
+	s.Lock()
+	defer s.Unlock()
+	return append([]result(nil), s.results...)
+}
+
+// Returns the results of the mazes finished since t
+func (s *scoreStore) since(t time.Time) []result {
+	s.Lock()
+	defer s.Unlock()
+	var rs []result
+	for _, r := range s.results {
+		if !r.Time.Before(t) {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// Empties the store, moving its file aside
+func (s *scoreStore) reset() error {
+	s.Lock()
+	defer s.Unlock()
+	s.results = nil
+	if s.path == "" {
+		return nil
+	}
+	err := os.Rename(s.path, s.path+"."+time.Now().Format("20060102-150405"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"math/rand"
+
+	"bitbucket.org/mannih/gc6/mazelib/solve"
+	"github.com/spf13/viper"
+)
+
+// The qlearning table of the current run, loaded on first use
+var learned *solve.QTable
+
+// the names of the strategies newStrategy knows
+var strategyNames = []string{"dfs", "montecarlo", "qlearning"}
+
+// Creates a fresh strategy for a single maze, making its random choices
+// with rng, or random numbers seeded by the clock if it is nil
+func newStrategy(name string, rng *rand.Rand) (solve.Strategy, error) {
+	switch name {
+	case "dfs":
+		return &solve.DFS{Bias: solve.Bias{
+			Straight: viper.GetFloat64("bias-straight"),
+			Center:   viper.GetFloat64("bias-center"),
+			Spiral:   viper.GetFloat64("bias-spiral"),
+		}, Rand: rng}, nil
+	case "montecarlo":
+		return &solve.MonteCarlo{Temperature: viper.GetFloat64("temperature"), Rand: rng}, nil
+	case "qlearning":
+		if learned == nil {
+			t, err := solve.LoadQTable(viper.GetString("qtable"))
+			if err != nil {
+				return nil, err
+			}
+			learned = t
+		}
+		return &solve.QLearning{Table: learned, Epsilon: viper.GetFloat64("epsilon"), Rand: rng}, nil
+	}
+	return nil, fmt.Errorf("unknown strategy %q", name)
+}
+
+// Saves what the qlearning strategy learned in this run, if it was used
+func saveLearned() {
+	if learned != nil {
+		if err := learned.Save(viper.GetString("qtable")); err != nil {
+			icarusLog.Error("couldn't save what the qlearning strategy learned", "err", err)
+		}
+	}
+}
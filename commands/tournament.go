@@ -0,0 +1,130 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"text/tabwriter"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/gen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the tournament command.
+// This will be called as 'laybrinth tournament'
+var tournamentCmd = &cobra.Command{
+	Use:   "tournament",
+	Short: "Pit every solver strategy against every maze algorithm",
+	Long: `The tournament lets Icarus solve laybrinths of every algorithm Daedalus
+  knows with every strategy he knows, as many times as asked for, and prints
+  the average steps each strategy took on each algorithm, followed by the
+  Elo ratings of both.
+
+  Both are played in this process, no server is started. Every strategy gets
+  the same mazes of an algorithm, so the results can be compared.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RunTournament()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(tournamentCmd)
+}
+
+func RunTournament() {
+	settings := currentSettings()
+	if err := settings.validate(); err != nil {
+		daedalusLog.Error("can't create mazes", "err", err)
+		return
+	}
+
+	algorithms := gen.Names()
+
+	// results[algorithm][strategy] is the average steps of the strategy on mazes of the algorithm
+	results := map[string]map[string]int{}
+	for _, alg := range algorithms {
+		seeds := make([]int64, viper.GetInt("times"))
+		for i := range seeds {
+			seeds[i] = rand.Int63()
+		}
+
+		results[alg] = map[string]int{}
+		for _, name := range strategyNames {
+			s := settings
+			s.Algorithm = alg
+			scores, err := playSeeds(name, seeds, s)
+			if err != nil {
+				icarusLog.Error("couldn't play the tournament", "strategy", name, "err", err)
+				return
+			}
+			results[alg][name] = mazelib.AvgScores(scores)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "algorithm\t")
+	for _, name := range strategyNames {
+		fmt.Fprintf(w, "%s\t", name)
+	}
+	fmt.Fprintln(w)
+	for _, alg := range algorithms {
+		fmt.Fprintf(w, "%s\t", alg)
+		for _, name := range strategyNames {
+			fmt.Fprintf(w, "%d\t", results[alg][name])
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+
+	r := rate(store.all())
+	fmt.Println()
+	printRatings("algorithm", r.Algorithms)
+	fmt.Println()
+	printRatings("strategy", r.Strategies)
+}
+
+// Prints the Elo ratings, best first
+func printRatings(kind string, ratings []rating) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "%s\trating\tmazes\t\n", kind)
+	for _, r := range ratings {
+		fmt.Fprintf(w, "%s\t%d\t%d\t\n", r.Name, r.Rating, r.Mazes)
+	}
+	w.Flush()
+}
+
+// Lets the strategy solve a maze created with the settings for each of the
+// seeds, making its choices with random numbers of the seed as well.
+// Returns the steps it took in each, unsolved mazes counting as max-steps.
+func playSeeds(strategy string, seeds []int64, s mazeSettings) ([]int, error) {
+	l := newLocalGame(s.Algorithm+"-"+strategy, strategy, s)
+	for _, seed := range seeds {
+		strat, err := newStrategy(strategy, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			return nil, err
+		}
+		l.settings.Seed = seed
+		if _, err := solveMaze(l, strat, s.Width*s.Height); err != nil {
+			return nil, err
+		}
+	}
+	l.finish()
+	return l.game.scores, nil
+}
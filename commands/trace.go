@@ -0,0 +1,138 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Every move Icarus tries is traced, including the ones daedalus refused.
+// Along with the maze the trace is enough to replay a game and to check
+// that its score is legit. Traces are handed out by /trace once their maze
+// is over, for the last keep-traces mazes of a session, and written to
+// trace-dir if it is set, which keeps all of them.
+
+// traceStep is a single move Icarus tried
+type traceStep struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	// where Icarus is after the move
+	Icarus    mazelib.Coordinate `json:"icarus"`
+	Victory   bool               `json:"victory,omitempty"`
+	ErrorCode string             `json:"error_code,omitempty"`
+}
+
+// mazeTrace is the maze along with every move tried in it
+type mazeTrace struct {
+	mazeView
+	Moves []traceStep `json:"moves"`
+}
+
+// Adds the move and daedalus's reply to it to the trace of the current maze
+func (g *game) trace(direction string, r mazelib.Reply) {
+	g.maze.trace = append(g.maze.trace, traceStep{
+		Time:      time.Now(),
+		Direction: direction,
+		Icarus:    g.maze.icarus,
+		Victory:   r.Victory,
+		ErrorCode: r.ErrorCode,
+	})
+}
+
+// Returns the trace of the current maze
+func (g *game) currentTrace() mazeTrace {
+	return mazeTrace{mazeView: g.view(), Moves: g.maze.trace}
+}
+
+// Keeps the trace of the current maze before it is dropped, along with the
+// ones of the mazes before it up to keep-traces, writing it to trace-dir if
+// there is one
+func (g *game) keepTrace() {
+	t := g.currentTrace()
+	if g.traces == nil {
+		g.traces = map[int]mazeTrace{}
+	}
+	if keep := daedalusConf.KeepTraces; keep > 0 {
+		g.traces[g.mazeID] = t
+		for id := range g.traces {
+			if id <= g.mazeID-keep {
+				delete(g.traces, id)
+			}
+		}
+	}
+
+	dir := daedalusConf.TraceDir
+	if dir == "" {
+		return
+	}
+	b, err := json.Marshal(t)
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("%s-%d.json", g.id, g.mazeID)), b, 0644)
+	}
+	if err != nil {
+		daedalusLog.Error("couldn't write the trace", "session", g.id, "maze", g.mazeID, "err", err)
+	}
+}
+
+// The API response to the /trace address.
+// Replies with the trace of the ?maze= numbered, by default the current one.
+// Mazes still being solved are kept secret.
+func GetTrace(c *Context) {
+	if t, ok := findTrace(c); ok {
+		respond(c, http.StatusOK, t)
+	}
+}
+
+// Finds the trace a request asks for, replying with an error if there is none
+func findTrace(c *Context) (mazeTrace, bool) {
+	g, ok := findGame(c)
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session, call /awake first", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return mazeTrace{}, false
+	}
+	g.Lock()
+	defer g.Unlock()
+
+	id := g.mazeID
+	if q := c.Query("maze"); q != "" {
+		var err error
+		if id, err = strconv.Atoi(q); err != nil {
+			respond(c, http.StatusBadRequest, mazelib.Reply{Error: true, Message: "maze has to be a number"})
+			return mazeTrace{}, false
+		}
+	}
+
+	if id == g.mazeID && g.maze != nil {
+		if !g.maze.solved && !g.maze.retired {
+			respond(c, http.StatusForbidden, mazelib.Reply{Error: true, Message: "the maze is still being solved"})
+			return mazeTrace{}, false
+		}
+		return g.currentTrace(), true
+	}
+	t, ok := g.traces[id]
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown maze"})
+	}
+	return t, ok
+}
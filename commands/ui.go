@@ -0,0 +1,221 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"image/color"
+	"net/http"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// The dashboard at /ui draws the maze of a session, Icarus and the path he
+// took, following the game as he moves. It gets the maze from /ui/maze,
+// drawn at /ui/maze.svg, and redraws it whenever /ui/events tells it
+// something happened. /ui/maze.png draws the same as a snapshot to embed.
+// Without a session in the query it follows the game started last.
+
+// mazeView is everything the dashboard draws
+type mazeView struct {
+	Session string `json:"session"`
+	Maze    int    `json:"maze"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	// the algorithm and seed that generated the maze
+	Algorithm string `json:"algorithm"`
+	Seed      int64  `json:"seed"`
+	// the walls of every room, by row
+	Walls    [][]mazelib.Survey   `json:"walls"`
+	Start    mazelib.Coordinate   `json:"start"`
+	Treasure mazelib.Coordinate   `json:"treasure"`
+	Icarus   mazelib.Coordinate   `json:"icarus"`
+	Path     []mazelib.Coordinate `json:"path"`
+	Steps    int                  `json:"steps"`
+	Solved   bool                 `json:"solved"`
+}
+
+// The API response to the /ui/maze address
+func GetMazeView(c *Context) {
+	g, ok := games.get(c.Query("session"))
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "no game to show yet", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	if g.maze == nil {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "no maze being solved", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+	respond(c, http.StatusOK, g.view())
+}
+
+// the size of a room and the color of Icarus's path on the dashboard
+const dashboardRoomSize = 30
+
+var dashboardPathColor = color.RGBA{0xf0, 0x90, 0x90, 0xff}
+
+// The API response to the /ui/maze.svg address
+func GetMazeSVG(c *Context) {
+	serveMazeImage(c, "image/svg+xml", mazelib.RenderSVG)
+}
+
+// The API response to the /ui/maze.png address, a snapshot of the maze
+// for anything that can't show SVG
+func GetMazePNG(c *Context) {
+	serveMazeImage(c, "image/png", mazelib.RenderPNG)
+}
+
+// Draws the current maze of the game in the query with its path and Icarus
+func serveMazeImage(c *Context, contentType string, render func(mazelib.MazeI, mazelib.RenderOptions) ([]byte, error)) {
+	g, ok := games.get(c.Query("session"))
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "no game to show yet", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	if g.maze == nil {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "no maze being solved", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+	path := append([]mazelib.Coordinate{g.maze.start}, g.maze.path...)
+	b, err := render(g.maze, mazelib.RenderOptions{
+		RoomSize: dashboardRoomSize,
+		Overlays: []mazelib.Overlay{{Path: path, Color: dashboardPathColor}},
+		Icarus:   true,
+	})
+	if err != nil {
+		respond(c, http.StatusInternalServerError, mazelib.Reply{Error: true, Message: err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, b)
+}
+
+// Describes the current maze of the game for the dashboard
+func (g *game) view() mazeView {
+	v := g.maze.view()
+	v.Session = g.id
+	v.Maze = g.mazeID
+	return v
+}
+
+// Describes the maze, without the game it is played in
+func (m *Maze) view() mazeView {
+	v := mazeView{
+		Width:     m.Width(),
+		Height:    m.Height(),
+		Algorithm: m.algorithm,
+		Seed:      m.seed,
+		Walls:     make([][]mazelib.Survey, m.Height()),
+		Start:     m.start,
+		Treasure:  m.end,
+		Icarus:    m.icarus,
+		Path:      append([]mazelib.Coordinate{m.start}, m.path...),
+		Steps:     m.StepsTaken,
+		Solved:    m.solved,
+	}
+	for i, r := range m.rooms {
+		y := i / m.width
+		v.Walls[y] = append(v.Walls[y], r.Walls)
+	}
+	return v
+}
+
+// Builds the maze the view describes, with Icarus at its start
+func (v mazeView) maze() *Maze {
+	m := &Maze{
+		rooms:     make([]mazelib.Room, 0, v.Width*v.Height),
+		width:     v.Width,
+		start:     v.Start,
+		end:       v.Treasure,
+		icarus:    v.Start,
+		algorithm: v.Algorithm,
+		seed:      v.Seed,
+	}
+	for _, row := range v.Walls {
+		for _, s := range row {
+			m.rooms = append(m.rooms, mazelib.Room{Walls: s})
+		}
+	}
+	m.rooms[v.Start.Y*m.width+v.Start.X].Start = true
+	m.rooms[v.Treasure.Y*m.width+v.Treasure.X].Treasure = true
+	return m
+}
+
+// The API response to the /ui/events address.
+// Unlike /events it doesn't need an api key, just like the rest of the dashboard.
+func FollowDashboard(c *Context) {
+	followEvents(c, c.Query("session"))
+}
+
+// The API response to the /ui address
+func ShowDashboard(c *Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(dashboard))
+}
+
+const dashboard = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Daedalus</title>
+<style>
+  body { font-family: sans-serif; background: #222; color: #eee; }
+</style>
+</head>
+<body>
+<h1>Daedalus</h1>
+<p id="status">waiting for Icarus to awake</p>
+<img id="maze" alt="">
+<script>
+var session = new URLSearchParams(location.search).get("session") || "";
+
+function draw(m) {
+  // every move changes the steps, so the image isn't the one drawn before
+  document.getElementById("maze").src = "/ui/maze.svg?session=" +
+    encodeURIComponent(m.session) + "&maze=" + m.maze + "&steps=" + m.steps;
+  document.getElementById("status").textContent = "session " + m.session +
+    ", maze " + m.maze + ", " + m.steps + " steps" + (m.solved ? ", solved" : "");
+}
+
+var fetching = false, stale = false;
+
+// fetches the maze again, at most one request at a time
+function refresh() {
+  if (fetching) { stale = true; return; }
+  fetching = true;
+  fetch("/ui/maze?session=" + encodeURIComponent(session))
+    .then(function(r) { return r.ok ? r.json() : null; })
+    .then(function(m) { if (m) { draw(m); } })
+    .catch(function() {})
+    .then(function() {
+      fetching = false;
+      if (stale) { stale = false; refresh(); }
+    });
+}
+
+var stream = new EventSource("/ui/events?session=" + encodeURIComponent(session));
+["awake", "move", "wall", "victory"].forEach(function(type) {
+  stream.addEventListener(type, refresh);
+});
+refresh();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,145 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Version 2 of the API.
+// Sessions and mazes are resources created with POST, moves are POSTed
+// as a mazelib.MoveRequest and nothing with side effects is a GET anymore.
+//
+//	POST   /v2/sessions                          start a session
+//	POST   /v2/sessions/:session/mazes           start the next maze of a session
+//	POST   /v2/sessions/:session/mazes/:maze/moves  walk in a maze
+//	GET    /v2/sessions/:session/mazes/:maze/reveal  the whole maze, once it is solved
+//	DELETE /v2/sessions/:session                 end a session, replying with its results
+//	GET    /v2/scores                            the leaderboard, ?session= for a single session
+//	GET    /v2/stats                             what is going on on the server right now
+//	GET    /v2/events                            follow the games as server-sent events
+
+func addV2Routes(v2 *routeGroup) {
+	v2.POST("/sessions", CreateSession)
+	v2.POST("/sessions/:session/mazes", CreateMaze)
+	v2.POST("/sessions/:session/mazes/:maze/moves", PostMoves)
+	v2.GET("/sessions/:session/mazes/:maze/reveal", RevealMazeV2)
+	v2.DELETE("/sessions/:session", DeleteSession)
+	v2.GET("/scores", GetScores)
+	v2.GET("/stats", GetStats)
+	v2.GET("/events", StreamEvents)
+}
+
+// mazeResource describes a maze created through the v2 API
+type mazeResource struct {
+	ID      int            `json:"id"`
+	Session string         `json:"session"`
+	Survey  mazelib.Survey `json:"survey"`
+}
+
+// The API response to POST /v2/sessions
+func CreateSession(c *Context) {
+	g := games.create(c.GetString("client"))
+	c.Header("Location", "/v2/sessions/"+g.id)
+	respond(c, http.StatusCreated, map[string]string{"session": g.id})
+}
+
+// The API response to POST /v2/sessions/:session/mazes.
+// Starting a new maze retires the current one. The maze can be configured
+// with the same query parameters as /awake.
+func CreateMaze(c *Context) {
+	settings, err := requestedSettings(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, mazelib.Reply{Error: true, Message: err.Error()})
+		return
+	}
+
+	g, ok := findGameByID(c, c.Param("session"))
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+	g.Lock()
+	defer g.Unlock()
+	g.strategy = c.GetHeader("X-Strategy")
+
+	startRoom, err := g.startMaze(settings)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, mazelib.Reply{Error: true, Message: err.Error()})
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/v2/sessions/%s/mazes/%d", g.id, g.mazeID))
+	respond(c, http.StatusCreated, mazeResource{ID: g.mazeID, Session: g.id, Survey: startRoom})
+}
+
+// The API response to POST /v2/sessions/:session/mazes/:maze/moves.
+// Replies with the replies to every step taken. A step running into a wall
+// makes the status 409, an invalid direction 400, moves in a maze that is no longer the current one
+// of the session get 410.
+func PostMoves(c *Context) {
+	g, ok := findGameByID(c, c.Param("session"))
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+
+	var req mazelib.MoveRequest
+	if err := c.BindJSON(&req); err != nil {
+		// BindJSON already replied with 400
+		return
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	id, err := strconv.Atoi(c.Param("maze"))
+	if err != nil || id < 1 || id > g.mazeID {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown maze", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+	if id != g.mazeID || g.maze == nil {
+		respond(c, http.StatusGone, mazelib.Reply{Error: true, Message: "maze is no longer being played", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+
+	replies := g.moveAll(req.Directions)
+	status := http.StatusOK
+	if len(replies) > 0 {
+		status = statusFor(replies[len(replies)-1])
+	}
+	respond(c, status, replies)
+}
+
+// The API response to DELETE /v2/sessions/:session
+func DeleteSession(c *Context) {
+	g, ok := findGameByID(c, c.Param("session"))
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+
+	g.Lock()
+	results := g.finish()
+	g.notifyEnd("was deleted")
+	g.Unlock()
+
+	respond(c, http.StatusOK, results)
+}
@@ -0,0 +1,123 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/cobra"
+)
+
+// Defining the validate command.
+// This will be called as 'laybrinth validate <maze file>'
+var validateCmd = &cobra.Command{
+	Use:   "validate <maze file>",
+	Short: "Check a laybrinth in a file makes sense",
+	Long: `Validate checks the laybrinth in a file written by generate, or the one
+  of a trace, or one drawn by hand in a .txt file the way generate prints
+  laybrinths: that it has every room, a start and a treasure inside of it,
+  walls all around, walls that look the same from both sides and a way from
+  the start to the treasure. It prints what it found and exits with 1 if
+  anything is wrong.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+		t, err := readTrace(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if !printChecks(t.checks()) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(validateCmd)
+}
+
+// check is the outcome of checking a single property of a maze
+type check struct {
+	name     string
+	problems []string
+	skipped  bool
+}
+
+// Checks everything about the maze validate does.
+// Without every room there is nothing else to check.
+func (v mazeView) checks() []check {
+	size := check{name: "every room"}
+	if err := v.layout().Whole(); err != nil {
+		size.problems = []string{err.Error()}
+		return []check{size,
+			{name: "start and treasure", skipped: true},
+			{name: "outer walls", skipped: true},
+			{name: "matching walls", skipped: true},
+			{name: "solvable", skipped: true},
+		}
+	}
+
+	placed := check{name: "start and treasure"}
+	if err := v.layout().Placed(); err != nil {
+		placed.problems = []string{err.Error()}
+	}
+
+	// the start and treasure may be anywhere, so only the walls are laid out
+	walls := mazeOf(v.Walls)
+	defer walls.release()
+	outer := check{name: "outer walls"}
+	matching := check{name: "matching walls"}
+	for _, e := range mazelib.CheckWalls(walls) {
+		if e.Outer {
+			outer.problems = append(outer.problems, e.Error())
+		} else {
+			matching.problems = append(matching.problems, e.Error())
+		}
+	}
+
+	solvable := check{name: "solvable"}
+	if placed.problems != nil {
+		solvable.skipped = true
+	} else if v.maze().shortestPath(v.Start, v.Treasure) == nil {
+		solvable.problems = []string{"there is no way from the start to the treasure"}
+	}
+
+	return []check{size, placed, outer, matching, solvable}
+}
+
+// Prints the outcome of the checks, returns whether all of them passed
+func printChecks(checks []check) bool {
+	ok := true
+	for _, c := range checks {
+		switch {
+		case c.skipped:
+			fmt.Printf("%-20s skipped\n", c.name)
+		case len(c.problems) == 0:
+			fmt.Printf("%-20s ok\n", c.name)
+		default:
+			ok = false
+			fmt.Printf("%-20s FAILED\n", c.name)
+			fmt.Println("  " + strings.Join(c.problems, "\n  "))
+		}
+	}
+	return ok
+}
@@ -0,0 +1,55 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"runtime"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/cobra"
+)
+
+// The build fills these in with
+//
+//	go build -ldflags "-X bitbucket.org/mannih/gc6/commands.Version=1.0.0
+//	  -X bitbucket.org/mannih/gc6/commands.Commit=$(git rev-parse --short HEAD)
+//	  -X bitbucket.org/mannih/gc6/commands.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Defining the version command.
+// This will be called as 'laybrinth version'
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of this laybrinth",
+	Long: `Version prints the version, git commit and build time of this binary and
+  the newest version of the API daedalus and icarus speak. Please add it to
+  bug reports.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("laybrinth %s\n", Version)
+		fmt.Printf("  commit:   %s\n", Commit)
+		fmt.Printf("  built:    %s with %s\n", BuildTime, runtime.Version())
+		fmt.Printf("  protocol: v%d\n", mazelib.ProtocolVersion)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,169 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"strings"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Defining the visualize command.
+// This will be called as 'laybrinth visualize <maze file>'
+var visualizeCmd = &cobra.Command{
+	Use:   "visualize <maze file>",
+	Short: "Draw a laybrinth as an SVG, PNG or GIF image or a graph",
+	Long: `Visualize draws the laybrinth in a file written by generate, or the one
+  of a trace, as an SVG or PNG image, which unlike the terminal works for
+  laybrinths of any size. The start is marked green and the treasure gold.
+  With --solution the shortest way from one to the other is drawn as well.
+  As a GIF the moves of a trace are animated, watch-delay per frame. As dot
+  the rooms and passages are written as a Graphviz graph instead, to see
+  how they connect with neato or dot.
+  The rooms are room-size pixels wide, and --colors sets the colors of the
+  background, wall, start, treasure, icarus, path and walk, the way Icarus
+  came in a GIF, like wall=#000000.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+		if err := visualize(args[0], viper.GetString("format"), viper.GetString("out")); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	visualizeCmd.Flags().StringP("out", "o", "", "file the laybrinth is drawn to")
+	visualizeCmd.Flags().String("format", "svg", "format the laybrinth is drawn in, svg, png, gif or dot")
+	visualizeCmd.Flags().Int("room-size", 16, "size of a room in the image, in pixels, at least 2")
+	visualizeCmd.Flags().StringSlice("colors", nil, "colors the background, wall, start, treasure, icarus, path and walk are drawn in, like wall=#000000")
+	visualizeCmd.Flags().Bool("solution", false, "draw the shortest way to the treasure")
+	RootCmd.AddCommand(visualizeCmd)
+}
+
+// the colors the way to the treasure and the way Icarus came are drawn in
+var (
+	pathColor = color.RGBA{0xd0, 0x3b, 0x3b, 0xff}
+	walkColor = color.RGBA{0x5b, 0x8d, 0xd9, 0xff}
+)
+
+// Draws the maze in the file as an image of the format, written to out
+func visualize(file, format, out string) error {
+	t, err := loadTrace(file)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		return fmt.Errorf("visualize needs a file to write the image to, use --out")
+	}
+
+	var solution []mazelib.Coordinate
+	if viper.GetBool("solution") {
+		solution = t.maze().shortestPath(t.Start, t.Treasure)
+	}
+
+	opts, walk, err := renderOptions(solution)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	switch format {
+	case "svg":
+		if b, err = mazelib.RenderSVG(t.maze(), opts); err != nil {
+			return err
+		}
+	case "png":
+		if b, err = mazelib.RenderPNG(t.maze(), opts); err != nil {
+			return err
+		}
+	case "dot":
+		if b, err = mazelib.RenderDOT(t.maze(), opts); err != nil {
+			return err
+		}
+	case "gif":
+		if len(t.Moves) == 0 {
+			return fmt.Errorf("%s has no moves to animate", file)
+		}
+		if b, err = t.animate(opts, walk); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q, use svg, png, gif or dot", format)
+	}
+	return ioutil.WriteFile(out, b, 0644)
+}
+
+// Draws Icarus walking through the maze the way he did, in the color of walk
+func (t mazeTrace) animate(opts mazelib.RenderOptions, walk color.RGBA) ([]byte, error) {
+	path := []mazelib.Coordinate{t.Start}
+	for _, m := range t.Moves {
+		path = append(path, m.Icarus)
+	}
+	return mazelib.RenderGIF(t.maze(), mazelib.Overlay{Path: path, Color: walk}, opts, viper.GetDuration("watch-delay"))
+}
+
+// Returns the options the images are drawn with, the room-size and colors
+// flags, with the path drawn over the maze if there is one, and the color
+// of the way Icarus came in animations
+func renderOptions(path []mazelib.Coordinate) (mazelib.RenderOptions, color.RGBA, error) {
+	opts := mazelib.RenderOptions{RoomSize: viper.GetInt("room-size"), Palette: mazelib.DefaultPalette}
+	pc, wc := pathColor, walkColor
+	colors := map[string]*color.RGBA{
+		"background": &opts.Palette.Background,
+		"wall":       &opts.Palette.Wall,
+		"start":      &opts.Palette.Start,
+		"treasure":   &opts.Palette.Treasure,
+		"icarus":     &opts.Palette.Icarus,
+		"path":       &pc,
+		"walk":       &wc,
+	}
+	for _, kv := range viper.GetStringSlice("colors") {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			return opts, wc, fmt.Errorf("colors are given as name=#rrggbb, not %q", kv)
+		}
+		c, ok := colors[kv[:i]]
+		if !ok {
+			return opts, wc, fmt.Errorf("unknown color %q, use background, wall, start, treasure, icarus, path or walk", kv[:i])
+		}
+		parsed, err := parseColor(kv[i+1:])
+		if err != nil {
+			return opts, wc, err
+		}
+		*c = parsed
+	}
+	if len(path) > 0 {
+		opts.Overlays = []mazelib.Overlay{{Path: path, Color: pc}}
+	}
+	return opts, wc, nil
+}
+
+// Parses a color written like #rrggbb, as in SVG and HTML
+func parseColor(s string) (color.RGBA, error) {
+	var c color.RGBA
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil || len(s) != 7 {
+		return c, fmt.Errorf("%q isn't a color like #rrggbb", s)
+	}
+	c.A = 0xff
+	return c, nil
+}
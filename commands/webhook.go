@@ -0,0 +1,117 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/spf13/viper"
+)
+
+// With a webhook configured daedalus posts a message to it whenever a
+// session ends, and whenever Icarus solves a maze in fewer steps than ever
+// before. The messages are JSON with the summary in a text field, which is
+// what Slack incoming webhooks and most chat tools understand.
+
+// message is what is posted to the webhook
+type message struct {
+	Text string `json:"text"`
+}
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// the notifications still being sent, waited for before daedalus exits
+var notifications sync.WaitGroup
+
+// the fewest steps a maze was solved in, looked up in the store the first time it is needed
+var fewest struct {
+	sync.Mutex
+	steps int
+	known bool
+}
+
+// Posts the text to the webhook, if one is configured.
+// It is sent in the background, so the game doesn't wait for it.
+func notify(text string) {
+	url := viper.GetString("webhook")
+	if url == "" {
+		return
+	}
+
+	body, err := json.Marshal(message{Text: text})
+	if err != nil {
+		daedalusLog.Error("couldn't create the webhook notification", "err", err)
+		return
+	}
+	notifications.Add(1)
+	go func() {
+		defer notifications.Done()
+		response, err := webhookClient.Post(url, mimeJSON, bytes.NewReader(body))
+		if err != nil {
+			daedalusLog.Error("couldn't notify the webhook", "url", url, "err", err)
+			return
+		}
+		response.Body.Close()
+		if response.StatusCode >= 300 {
+			daedalusLog.Error("the webhook refused the notification", "url", url, "status", response.Status)
+		}
+	}()
+}
+
+// Tells the webhook the game has ended, why and how it went
+func (g *game) notifyEnd(why string) {
+	if viper.GetString("webhook") == "" {
+		return
+	}
+	s := scoring().Score(g.scores)
+	st := mazelib.Statistics(g.scores, histogramBuckets)
+	notify(fmt.Sprintf("Session %s %s: %d mazes, %d solved, score %d, avg %d steps, median %d, p90 %d",
+		g.id, why, s.Mazes, s.Solved, s.Final, st.Mean, st.Median, st.P90))
+}
+
+// Tells the webhook if the current maze was solved in fewer steps than any before.
+// Has to be called before the maze is recorded.
+func (g *game) notifyRecord() {
+	if viper.GetString("webhook") == "" {
+		return
+	}
+
+	fewest.Lock()
+	defer fewest.Unlock()
+	if !fewest.known {
+		for _, r := range store.all() {
+			if r.Solved && (!fewest.known || r.Steps < fewest.steps) {
+				fewest.steps = r.Steps
+				fewest.known = true
+			}
+		}
+	}
+
+	steps := g.maze.StepsTaken
+	if fewest.known && steps >= fewest.steps {
+		return
+	}
+	fewest.steps = steps
+	fewest.known = true
+	notify(fmt.Sprintf("New record: session %s solved a %dx%d %s maze in %d steps",
+		g.id, g.maze.Width(), g.maze.Height(), g.maze.algorithm, steps))
+}
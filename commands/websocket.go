@@ -0,0 +1,118 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package commands
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"github.com/gorilla/websocket"
+)
+
+// Moves can be streamed over a single websocket instead of making a
+// request for every one of them.
+// The client wakes up with /awake as usual and then connects to /ws with
+// the id of its session. Every mazelib.MoveRequest it sends is answered
+// with the list of replies to each of its steps, the same Icarus would get
+// from /batch. The connection stays usable for the following mazes of the
+// session.
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Icarus isn't a browser, so there is no origin to check
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Icarus's websockets reach daedalus the same way his requests do
+var dialer = &websocket.Dialer{
+	HandshakeTimeout: 10 * time.Second,
+	NetDialContext:   dial,
+}
+
+// The API response to the /ws address
+func StreamMoves(c *Context) {
+	g, ok := findGame(c)
+	if !ok {
+		respond(c, http.StatusNotFound, mazelib.Reply{Error: true, Message: "unknown session, call /awake first", ErrorCode: mazelib.ErrCodeNoActiveMaze})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// the upgrader already replied with an error
+		return
+	}
+	defer conn.Close()
+
+	for {
+		var req mazelib.MoveRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		if err := conn.WriteJSON(g.moveStreamed(req.Directions)); err != nil {
+			return
+		}
+	}
+}
+
+// Moves Icarus along the directions of a streamed request like moveAll, or
+// tells him there is no maze to move in. Locks the game while moving.
+func (g *game) moveStreamed(directions []string) []mazelib.Reply {
+	g.Lock()
+	defer g.Unlock()
+	if g.maze == nil {
+		return []mazelib.Reply{{Error: true, Message: mazelib.ErrNoMaze.Error(), ErrorCode: mazelib.ErrorCode(mazelib.ErrNoMaze)}}
+	}
+	return g.moveAll(directions)
+}
+
+// Sends directions to daedalus over the session's websocket,
+// connecting first if needed.
+func (sess *session) stream(directions []string) ([]mazelib.Reply, error) {
+	if sess.conn == nil {
+		u := sess.url("/ws")
+		u = "ws" + strings.TrimPrefix(u, "http")
+		conn, _, err := dialer.Dial(u, requestHeader())
+		if err != nil {
+			return nil, &connectionError{u, err}
+		}
+		sess.conn = conn
+	}
+
+	var replies []mazelib.Reply
+	err := sess.conn.WriteJSON(mazelib.MoveRequest{Directions: directions})
+	if err == nil {
+		err = sess.conn.ReadJSON(&replies)
+	}
+	if err != nil {
+		// connect again with the next move
+		sess.close()
+		return nil, &connectionError{"/ws", err}
+	}
+	return replies, nil
+}
+
+// Closes the session's websocket, if there is one
+func (sess *session) close() {
+	if sess.conn != nil {
+		sess.conn.Close()
+		sess.conn = nil
+	}
+}
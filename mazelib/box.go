@@ -0,0 +1,126 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"io"
+	"strings"
+)
+
+// the box-drawing characters joining the walls meeting at a corner,
+// indexed by which of them are there: 1 up, 2 down, 4 left and 8 right
+var boxCorners = []string{" ", "╵", "╷", "│", "╴", "┘", "┐", "┤", "╶", "└", "┌", "├", "─", "┴", "┬", "┼"}
+
+// FprintBoxMaze draws the maze to w with box-drawing characters, a
+// character for every room and one for every wall between them.
+// The start is shown as ⏀ and the treasure as ⏃, like PrintMaze does.
+func FprintBoxMaze(w io.Writer, m MazeI) error {
+	return fprintBoxMaze(w, m, plainRoom)
+}
+
+// Draws the maze like FprintBoxMaze does, with the rooms drawn by draw
+func fprintBoxMaze(w io.Writer, m MazeI, draw roomDrawing) error {
+	width, height := m.Width(), m.Height()
+	rooms := make([][]Survey, height)
+	for y := range rooms {
+		rooms[y] = make([]Survey, width)
+		for x := range rooms[y] {
+			s, err := m.Discover(x, y)
+			if err != nil {
+				return err
+			}
+			rooms[y][x] = s
+		}
+	}
+
+	// whether there is a wall above and left of the room at x, y, which
+	// may be the walls below and right of the last row and column
+	above := func(x, y int) bool {
+		if x < 0 || x >= width {
+			return false
+		}
+		if y == height {
+			return rooms[y-1][x].Bottom
+		}
+		return rooms[y][x].Top || (y > 0 && rooms[y-1][x].Bottom)
+	}
+	left := func(x, y int) bool {
+		if y < 0 || y >= height {
+			return false
+		}
+		if x == width {
+			return rooms[y][x-1].Right
+		}
+		return rooms[y][x].Left || (x > 0 && rooms[y][x-1].Right)
+	}
+
+	var b strings.Builder
+	for y := 0; y <= height; y++ {
+		for x := 0; x <= width; x++ {
+			corner := 0
+			if left(x, y-1) {
+				corner |= 1
+			}
+			if left(x, y) {
+				corner |= 2
+			}
+			if above(x-1, y) {
+				corner |= 4
+			}
+			if above(x, y) {
+				corner |= 8
+			}
+			b.WriteString(boxCorners[corner])
+			if x < width && above(x, y) {
+				b.WriteString("─")
+			} else if x < width {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+		if y == height {
+			break
+		}
+
+		for x := 0; x <= width; x++ {
+			if left(x, y) {
+				b.WriteString("│")
+			} else {
+				b.WriteString(" ")
+			}
+			if x == width {
+				break
+			}
+			r, err := m.GetRoom(x, y)
+			if err != nil {
+				return err
+			}
+			b.WriteString(draw(Coordinate{x, y}, r, " "))
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// SprintBoxMaze draws the maze the way FprintBoxMaze does and returns the drawing
+func SprintBoxMaze(m MazeI) string {
+	var b strings.Builder
+	if err := FprintBoxMaze(&b, m); err != nil {
+		return err.Error()
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"io"
+	"strings"
+)
+
+// the ANSI escape codes colored mazes are drawn with
+const (
+	ansiReset    = "\033[0m"
+	ansiStart    = "\033[1;32m" // bold green
+	ansiTreasure = "\033[1;33m" // bold yellow
+	ansiIcarus   = "\033[1;31m" // bold red
+	ansiPath     = "\033[44m"   // blue background
+)
+
+// FprintColorMaze draws the maze the way FprintMaze does, for terminals
+// understanding ANSI escape codes: the start in green, the treasure in
+// yellow, Icarus as a red @ and the rooms of his path on blue.
+func FprintColorMaze(w io.Writer, m MazeI, path []Coordinate) error {
+	return fprintMaze(w, m, colorRooms(m, path))
+}
+
+// FprintColorBoxMaze draws the maze with box-drawing characters the way
+// FprintBoxMaze does, in the colors of FprintColorMaze
+func FprintColorBoxMaze(w io.Writer, m MazeI, path []Coordinate) error {
+	return fprintBoxMaze(w, m, colorRooms(m, path))
+}
+
+// SprintColorMaze draws the maze the way FprintColorMaze does and returns the drawing
+func SprintColorMaze(m MazeI, path []Coordinate) string {
+	var b strings.Builder
+	if err := FprintColorMaze(&b, m, path); err != nil {
+		return err.Error()
+	}
+	return b.String()
+}
+
+// Returns a drawing of the rooms in color, with Icarus where he is in the
+// maze and the rooms of the path marked
+func colorRooms(m MazeI, path []Coordinate) roomDrawing {
+	x, y := m.Icarus()
+	icarus := Coordinate{x, y}
+	visited := make(map[Coordinate]bool, len(path))
+	for _, c := range path {
+		visited[c] = true
+	}
+
+	return func(c Coordinate, r *Room, empty string) string {
+		switch {
+		case c == icarus:
+			return ansiIcarus + "@" + ansiReset + empty[1:]
+		case r.Treasure:
+			return ansiTreasure + plainRoom(c, r, empty) + ansiReset
+		case r.Start:
+			return ansiStart + plainRoom(c, r, empty) + ansiReset
+		case visited[c]:
+			return ansiPath + empty + ansiReset
+		}
+		return empty
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Equal tells whether the layouts are the same maze: the same size, walls,
+// start and treasure
+func (l Layout) Equal(o Layout) bool {
+	return len(l.Diff(o)) == 0
+}
+
+// Diff returns how the layout o differs from l, a line per difference the
+// way diff shows them: - for what only l has and + for o. Layouts of
+// different sizes aren't compared room by room.
+func (l Layout) Diff(o Layout) []string {
+	if l.Width != o.Width || l.Height != o.Height {
+		return []string{fmt.Sprintf("- %dx%d", l.Width, l.Height), fmt.Sprintf("+ %dx%d", o.Width, o.Height)}
+	}
+	var d []string
+	if l.Start != o.Start {
+		d = append(d, fmt.Sprintf("- start at %d,%d", l.Start.X, l.Start.Y), fmt.Sprintf("+ start at %d,%d", o.Start.X, o.Start.Y))
+	}
+	if l.Treasure != o.Treasure {
+		d = append(d, fmt.Sprintf("- treasure at %d,%d", l.Treasure.X, l.Treasure.Y), fmt.Sprintf("+ treasure at %d,%d", o.Treasure.X, o.Treasure.Y))
+	}
+	for y := 0; y < l.Height; y++ {
+		for x := 0; x < l.Width; x++ {
+			a, b := l.wall(x, y), o.wall(x, y)
+			for _, dir := range Directions {
+				mark := ""
+				switch {
+				case !a.HasExit(dir) && b.HasExit(dir):
+					mark = "-"
+				case a.HasExit(dir) && !b.HasExit(dir):
+					mark = "+"
+				default:
+					continue
+				}
+				d = append(d, fmt.Sprintf("%s room %d,%d wall to the %s", mark, x, y, sideNames[dir]))
+			}
+		}
+	}
+	return d
+}
+
+// Returns the walls of the room, if the layout has it
+func (l Layout) wall(x, y int) Survey {
+	if y < len(l.Walls) && x < len(l.Walls[y]) {
+		return l.Walls[y][x]
+	}
+	return Survey{}
+}
+
+// Hash returns a fingerprint of the layout, the same for every layout Equal
+// to it however it was made
+func (l Layout) Hash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%dx%d start %d,%d treasure %d,%d\n", l.Width, l.Height, l.Start.X, l.Start.Y, l.Treasure.X, l.Treasure.Y)
+	for y := 0; y < l.Height; y++ {
+		for x := 0; x < l.Width; x++ {
+			s, bits := l.wall(x, y), 0
+			for i, dir := range Directions {
+				if !s.HasExit(dir) {
+					bits |= 1 << uint(i)
+				}
+			}
+			fmt.Fprintf(h, "%x", bits)
+		}
+		fmt.Fprintln(h)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// CanonicalHash returns a fingerprint of the layout which is the same for
+// all of its Orientations, so a maze is recognized however it is turned
+func (l Layout) CanonicalHash() string {
+	best := ""
+	for _, o := range l.Orientations() {
+		if h := o.Hash(); best == "" || h < best {
+			best = h
+		}
+	}
+	return best
+}
@@ -0,0 +1,115 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import "fmt"
+
+// Direction is one of the four ways out of a room.
+// On the wire directions are the names up, down, left and right.
+type Direction int
+
+const (
+	N Direction = 1 // up
+	S Direction = 2 // down
+	E Direction = 3 // right
+	W Direction = 4 // left
+)
+
+// Directions are all four of them
+var Directions = []Direction{N, S, W, E}
+
+var directionNames = map[Direction]string{N: "up", S: "down", E: "right", W: "left"}
+
+// ParseDirection returns the direction with the given name
+func ParseDirection(name string) (Direction, error) {
+	for d, n := range directionNames {
+		if n == name {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid direction %q, use up, down, left or right", name)
+}
+
+// String returns the name of the direction, the way it is sent to daedalus
+func (d Direction) String() string {
+	if n, ok := directionNames[d]; ok {
+		return n
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// Opposite returns the direction leading back
+func (d Direction) Opposite() Direction {
+	switch d {
+	case N:
+		return S
+	case S:
+		return N
+	case E:
+		return W
+	case W:
+		return E
+	}
+	return d
+}
+
+// Clockwise returns the direction a right turn leads to
+func (d Direction) Clockwise() Direction {
+	switch d {
+	case N:
+		return E
+	case E:
+		return S
+	case S:
+		return W
+	case W:
+		return N
+	}
+	return d
+}
+
+// Delta returns how far a step in the direction moves along x and y
+func (d Direction) Delta() (dx, dy int) {
+	switch d {
+	case N:
+		return 0, -1
+	case S:
+		return 0, 1
+	case E:
+		return 1, 0
+	case W:
+		return -1, 0
+	}
+	return 0, 0
+}
+
+// MarshalText encodes the direction as its name, so it reads the same in JSON
+func (d Direction) MarshalText() ([]byte, error) {
+	if _, ok := directionNames[d]; !ok {
+		return nil, fmt.Errorf("can't encode %v", d)
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a direction from its name
+func (d *Direction) UnmarshalText(text []byte) error {
+	p, err := ParseDirection(string(text))
+	if err != nil {
+		return err
+	}
+	*d = p
+	return nil
+}
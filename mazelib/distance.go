@@ -0,0 +1,81 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+// DistanceMap returns the steps it takes to walk from the room at from to
+// every room of the maze, by row, with -1 for the rooms that can't be
+// reached. Walls are taken from the room being left.
+func DistanceMap(m MazeI, from Coordinate) [][]int {
+	dist := make([][]int, m.Height())
+	for y := range dist {
+		dist[y] = make([]int, m.Width())
+		for x := range dist[y] {
+			dist[y][x] = -1
+		}
+	}
+	g := GridOf(m)
+	if !g.Contains(from) {
+		return dist
+	}
+
+	dist[from.Y][from.X] = 0
+	queue := []Coordinate{from}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		s, err := m.Discover(c.X, c.Y)
+		if err != nil {
+			continue
+		}
+		for _, d := range g.Directions() {
+			n, ok := g.Neighbor(c, d)
+			if !s.HasExit(d) || !ok || dist[n.Y][n.X] >= 0 {
+				continue
+			}
+			dist[n.Y][n.X] = dist[c.Y][c.X] + 1
+			queue = append(queue, n)
+		}
+	}
+	return dist
+}
+
+// PathTo returns the shortest way from the room the distance map was made
+// from to the room at to, both included, or nil if it can't be reached.
+// Mazes with loops may have several, this is one of them.
+func PathTo(m MazeI, dist [][]int, to Coordinate) []Coordinate {
+	g := GridOf(m)
+	if !g.Contains(to) || dist[to.Y][to.X] < 0 {
+		return nil
+	}
+
+	// walk back through rooms ever closer to where the map was made from
+	path := make([]Coordinate, dist[to.Y][to.X]+1)
+	path[len(path)-1] = to
+	for i := len(path) - 1; i > 0; i-- {
+		c := path[i]
+		for _, d := range g.Directions() {
+			p, ok := g.Neighbor(c, d)
+			if !ok || dist[p.Y][p.X] != i-1 {
+				continue
+			}
+			if s, err := m.Discover(p.X, p.Y); err == nil && s.HasExit(d.Opposite()) {
+				path[i-1] = p
+				break
+			}
+		}
+	}
+	return path
+}
@@ -0,0 +1,94 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+)
+
+// RenderDOT writes the maze as an undirected Graphviz graph, with a node for
+// every room and an edge for every passage between two rooms. The start and
+// treasure are filled in and the passages of the overlays drawn thick in
+// their colors. Every node is pinned to its place in the maze, so neato lays
+// it out as a grid while dot shows how the rooms branch off.
+// The room size is of no use in a graph and left alone.
+func RenderDOT(m MazeI, opts RenderOptions) ([]byte, error) {
+	p := opts.withDefaults().Palette
+
+	// later overlays are drawn over earlier ones
+	overlaid := map[[2]Coordinate]color.RGBA{}
+	for _, o := range opts.Overlays {
+		for i := 1; i < len(o.Path); i++ {
+			overlaid[[2]Coordinate{o.Path[i-1], o.Path[i]}] = o.Color
+			overlaid[[2]Coordinate{o.Path[i], o.Path[i-1]}] = o.Color
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "graph maze {")
+	fmt.Fprintf(&b, "\tbgcolor=\"%s\";\n", hexColor(p.Background))
+	fmt.Fprintf(&b, "\tnode [shape=box, style=filled, color=\"%s\", fillcolor=\"%s\"];\n", hexColor(p.Wall), hexColor(p.Background))
+	fmt.Fprintf(&b, "\tedge [color=\"%s\"];\n", hexColor(p.Wall))
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			r, err := m.GetRoom(x, y)
+			if err != nil {
+				return nil, err
+			}
+			attrs := fmt.Sprintf("pos=\"%d,%d!\"", x, -y)
+			switch {
+			case r.Treasure:
+				attrs += fmt.Sprintf(", fillcolor=\"%s\"", hexColor(p.Treasure))
+			case r.Start:
+				attrs += fmt.Sprintf(", fillcolor=\"%s\"", hexColor(p.Start))
+			}
+			fmt.Fprintf(&b, "\t\"%d,%d\" [%s];\n", x, y, attrs)
+		}
+	}
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			c := Coordinate{x, y}
+			s, err := m.Discover(x, y)
+			if err != nil {
+				return nil, err
+			}
+			// every passage once, from the room left or above it, and
+			// only if neither room has a wall in the way
+			for _, d := range []Direction{E, S} {
+				n := c.Move(d)
+				if !s.HasExit(d) || !n.In(m.Width(), m.Height()) {
+					continue
+				}
+				o, err := m.Discover(n.X, n.Y)
+				if err != nil {
+					return nil, err
+				}
+				if !o.HasExit(d.Opposite()) {
+					continue
+				}
+				attrs := ""
+				if oc, ok := overlaid[[2]Coordinate{c, n}]; ok {
+					attrs = fmt.Sprintf(" [color=\"%s\", penwidth=3]", hexColor(oc))
+				}
+				fmt.Fprintf(&b, "\t\"%d,%d\" -- \"%d,%d\"%s;\n", x, y, n.X, n.Y, attrs)
+			}
+		}
+	}
+	fmt.Fprintln(&b, "}")
+	return b.Bytes(), nil
+}
@@ -0,0 +1,187 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+// Package gen has the algorithms daedalus generates laybrinths with.
+// Generators only lay out the walls of the rooms, where the start and the
+// treasure go is up to whoever uses them.
+package gen
+
+import (
+	"math/rand"
+	"sort"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Generator returns the walls of every room of a new maze, by row.
+// The same rng makes the same maze.
+type Generator func(rng *rand.Rand, width, height int) [][]mazelib.Survey
+
+// Generators are the algorithms by name
+var Generators = map[string]Generator{
+	"binarytree":       BinaryTree,
+	"binarytree-holes": BinaryTreeWithHoles,
+	"growingtree":      GrowingTree,
+}
+
+// Names returns the names of the generators, sorted
+func Names() []string {
+	var names []string
+	for name := range Generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Empty returns the walls of a maze without any
+// Good starting point for additive algorithms
+func Empty(width, height int) [][]mazelib.Survey {
+	walls := make([][]mazelib.Survey, height)
+	for y := range walls {
+		walls[y] = make([]mazelib.Survey, width)
+	}
+	return walls
+}
+
+// Full returns the walls of a maze with all of them
+// Good starting point for subtractive algorithms
+func Full(width, height int) [][]mazelib.Survey {
+	walls := Empty(width, height)
+	for y := range walls {
+		for x := range walls[y] {
+			walls[y][x] = mazelib.Survey{Top: true, Right: true, Bottom: true, Left: true}
+		}
+	}
+	return walls
+}
+
+// Takes down the wall between the room at c and the next one in the direction
+func carve(walls [][]mazelib.Survey, c mazelib.Coordinate, d mazelib.Direction) {
+	n := c.Move(d)
+	from, to := mazelib.Room{Walls: walls[c.Y][c.X]}, mazelib.Room{Walls: walls[n.Y][n.X]}
+	from.RmWall(d)
+	to.RmWall(d.Opposite())
+	walls[c.Y][c.X], walls[n.Y][n.X] = from.Walls, to.Walls
+}
+
+// BinaryTree connects every room to the one right or below it
+func BinaryTree(rng *rand.Rand, width, height int) [][]mazelib.Survey {
+	walls := Full(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := mazelib.Coordinate{X: x, Y: y}
+
+			dir := rng.Intn(2)
+			// if we are at the right boarder, we can only go down
+			if (y == height-1) && (x == width-1) {
+				break
+			} else if x == width-1 {
+				dir = 1
+			} else if y == height-1 {
+				dir = 0
+			}
+			switch dir {
+			case 0:
+				carve(walls, c, mazelib.E)
+			case 1:
+				carve(walls, c, mazelib.S)
+			}
+		}
+	}
+	return walls
+}
+
+// BinaryTreeWithHoles is based on the binary tree algorithm, but sometimes,
+// we add additional holes in the wall to create some loops
+func BinaryTreeWithHoles(rng *rand.Rand, width, height int) [][]mazelib.Survey {
+	walls := Full(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := mazelib.Coordinate{X: x, Y: y}
+
+			dir := rng.Intn(2)
+			// if we are at the right boarder, we can only go down
+			if (y == height-1) && (x == width-1) {
+				break
+			} else if x == width-1 {
+				dir = 1
+			} else if y == height-1 {
+				dir = 0
+			}
+			switch dir {
+			case 0:
+				carve(walls, c, mazelib.E)
+			case 1:
+				carve(walls, c, mazelib.S)
+			case 2:
+				carve(walls, c, mazelib.E)
+				carve(walls, c, mazelib.S)
+			}
+		}
+	}
+	return walls
+}
+
+// GrowingTree carves the maze from a random room, always going on from the
+// room it reached last until it runs out of unvisited neighbors
+func GrowingTree(rng *rand.Rand, width, height int) [][]mazelib.Survey {
+	// starting with a full maze
+	walls := Full(width, height)
+	// create an 2D array for visited cells
+	visited := make([][]bool, width)
+	for i := 0; i < width; i++ {
+		visited[i] = make([]bool, height)
+	}
+	// create an array for active cells
+	cells := make([]mazelib.Coordinate, 1)
+	//select a random starting point for the creation
+	y := rng.Intn(height - 1)
+	x := rng.Intn(width - 1)
+	cells[0] = mazelib.Coordinate{X: x, Y: y}
+	visited[x][y] = true
+	grid := mazelib.Rect{Width: width, Height: height}
+	dirs := grid.Directions()
+	// the room going from c in the direction leads to, if it wasn't visited yet
+	unvisited := func(c mazelib.Coordinate, d mazelib.Direction) (mazelib.Coordinate, bool) {
+		n, ok := grid.Neighbor(c, d)
+		return n, ok && !visited[n.X][n.Y]
+	}
+	for len(cells) > 0 {
+		//we use the newest cell to work with
+		active := cells[len(cells)-1]
+		//lets see if it has unvisited neighbors
+		done := true
+		for _, d := range dirs {
+			if _, ok := unvisited(active, d); ok {
+				done = false
+			}
+		}
+		if done {
+			cells = cells[:len(cells)-1]
+		}
+		//shuffle directions (up, down, left, right) and carve towards the first unvisited neighbor
+		for _, i := range rng.Perm(len(dirs)) {
+			d := dirs[i]
+			if n, ok := unvisited(active, d); ok {
+				cells = append(cells, n)
+				carve(walls, active, d)
+				visited[n.X][n.Y] = true
+				break
+			}
+		}
+	}
+	return walls
+}
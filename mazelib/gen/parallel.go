@@ -0,0 +1,74 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package gen
+
+import (
+	"math/rand"
+	"sync"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// the rows of the bands Parallel carves mazes in
+const bandHeight = 128
+
+// Parallel has the generator carve large mazes in bands of rows, up to
+// workers of them at the same time, then stitches every two neighboring
+// bands together through a single door, which keeps perfect mazes perfect.
+// The bands don't depend on the number of workers, so the same rng makes the
+// same maze with any of them, a single one included. Mazes of less than two
+// bands are a band of their own, carved in one go like the generator does.
+func Parallel(g Generator, workers int) Generator {
+	if workers < 1 {
+		workers = 1
+	}
+	return func(rng *rand.Rand, width, height int) [][]mazelib.Survey {
+		bands := height / bandHeight
+		if bands < 2 {
+			return g(rng, width, height)
+		}
+
+		// seeded in order, so it doesn't matter which band is carved first
+		seeds := make([]int64, bands)
+		for i := range seeds {
+			seeds[i] = rng.Int63()
+		}
+
+		walls := make([][]mazelib.Survey, height)
+		running := make(chan struct{}, workers)
+		var wg sync.WaitGroup
+		for i := 0; i < bands; i++ {
+			top, rows := i*bandHeight, bandHeight
+			if i == bands-1 {
+				// the last band takes the rows left over
+				rows = height - top
+			}
+			wg.Add(1)
+			running <- struct{}{}
+			go func(seed int64, top, rows int) {
+				defer wg.Done()
+				copy(walls[top:top+rows], g(rand.New(rand.NewSource(seed)), width, rows))
+				<-running
+			}(seeds[i], top, rows)
+		}
+		wg.Wait()
+
+		for i := 1; i < bands; i++ {
+			carve(walls, mazelib.Coordinate{X: rng.Intn(width), Y: i*bandHeight - 1}, mazelib.S)
+		}
+		return walls
+	}
+}
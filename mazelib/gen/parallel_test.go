@@ -0,0 +1,45 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package gen_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"bitbucket.org/mannih/gc6/mazelib/gen"
+)
+
+// The same seed makes the same maze with any number of workers, in mazes of
+// many bands, of bands with rows left over and of a single band
+func TestParallelWorkers(t *testing.T) {
+	sizes := []struct{ width, height int }{
+		{16, 512},
+		{20, 401},
+		{30, 40},
+	}
+	for _, name := range gen.Names() {
+		for _, s := range sizes {
+			one := gen.Parallel(gen.Generators[name], 1)(rand.New(rand.NewSource(1)), s.width, s.height)
+			for _, workers := range []int{0, 2, 4} {
+				got := gen.Parallel(gen.Generators[name], workers)(rand.New(rand.NewSource(1)), s.width, s.height)
+				if !reflect.DeepEqual(got, one) {
+					t.Errorf("%s %dx%d: the maze of %d workers differs from the one of a single worker", name, s.width, s.height, workers)
+				}
+			}
+		}
+	}
+}
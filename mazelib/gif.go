@@ -0,0 +1,74 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"time"
+)
+
+// RenderGIF draws Icarus walking the path of the walk through the maze as
+// an animated GIF, with a frame for every room of it shown for delay and
+// the way he came drawn behind him in the color of the walk. The maze is
+// drawn like RenderImage does, and the last frame is shown a second longer.
+func RenderGIF(m MazeI, walk Overlay, opts RenderOptions, delay time.Duration) ([]byte, error) {
+	opts = opts.withDefaults()
+	opts.Icarus = false
+	base, err := RenderImage(m, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p := opts.Palette
+	palette := color.Palette{p.Background, p.Wall, p.Start, p.Treasure, p.Icarus, walk.Color}
+	for _, o := range opts.Overlays {
+		palette = append(palette, o.Color)
+	}
+
+	trail := canvas{base, opts.RoomSize}
+	hundredths := int(delay / (10 * time.Millisecond))
+	anim := &gif.GIF{}
+	for i, r := range walk.Path {
+		if i > 0 {
+			trail.segment(walk.Path[i-1], r, walk.Color)
+		}
+		frame := canvas{image.NewRGBA(base.Bounds()), opts.RoomSize}
+		copy(frame.img.Pix, trail.img.Pix)
+		frame.dot(r, p.Icarus)
+
+		img := image.NewPaletted(base.Bounds(), palette)
+		draw.Draw(img, img.Bounds(), frame.img, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, hundredths)
+	}
+	if len(anim.Image) == 0 {
+		img := image.NewPaletted(base.Bounds(), palette)
+		draw.Draw(img, img.Bounds(), base, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, hundredths)
+	}
+	anim.Delay[len(anim.Delay)-1] += 100
+
+	var b bytes.Buffer
+	if err := gif.EncodeAll(&b, anim); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Render is a maze drawn in one of the formats, as a file of it is named
+type Render struct {
+	// the end of the file name, like .svg or .box.txt
+	Ext  string
+	Data []byte
+}
+
+// Renders draws the maze in every format there is, with the default
+// options and nothing drawn over it. The same maze always makes the same
+// bytes, so they can be kept as golden files and a change in where a
+// generator puts its walls shows up when they are diffed.
+func Renders(m MazeI) ([]Render, error) {
+	var text, box, color bytes.Buffer
+	if err := FprintMaze(&text, m); err != nil {
+		return nil, err
+	}
+	if err := FprintBoxMaze(&box, m); err != nil {
+		return nil, err
+	}
+	if err := FprintColorMaze(&color, m, nil); err != nil {
+		return nil, err
+	}
+	renders := []Render{{".txt", text.Bytes()}, {".box.txt", box.Bytes()}, {".color.txt", color.Bytes()}}
+
+	images := []struct {
+		ext    string
+		render func(MazeI, RenderOptions) ([]byte, error)
+	}{
+		{".svg", RenderSVG},
+		{".png", RenderPNG},
+		{".gif", func(m MazeI, opts RenderOptions) ([]byte, error) { return RenderGIF(m, Overlay{}, opts, 0) }},
+		{".dot", RenderDOT},
+	}
+	for _, i := range images {
+		b, err := i.render(m, RenderOptions{})
+		if err != nil {
+			return nil, err
+		}
+		renders = append(renders, Render{i.ext, b})
+	}
+	return renders, nil
+}
+
+// WriteRenders writes the Renders of the maze to dir, as files named name
+// followed by the ending of their format
+func WriteRenders(dir, name string, m MazeI) error {
+	renders, err := Renders(m)
+	if err != nil {
+		return err
+	}
+	for _, r := range renders {
+		if err := ioutil.WriteFile(filepath.Join(dir, name+r.Ext), r.Data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckRenders compares the Renders of the maze to the files WriteRenders
+// wrote to dir, and returns the names of the ones that differ or are missing
+func CheckRenders(dir, name string, m MazeI) ([]string, error) {
+	renders, err := Renders(m)
+	if err != nil {
+		return nil, err
+	}
+	var differ []string
+	for _, r := range renders {
+		file := name + r.Ext
+		b, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil && !os.IsNotExist(err) {
+			return differ, err
+		}
+		if err != nil || !bytes.Equal(b, r.Data) {
+			differ = append(differ, file)
+		}
+	}
+	return differ, nil
+}
@@ -0,0 +1,59 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/gen"
+	"bitbucket.org/mannih/gc6/mazelib/mazetest"
+)
+
+var update = flag.Bool("update", false, "write the golden files in testdata instead of comparing to them")
+
+// Every renderer draws the maze of a fixed seed as in its golden file,
+// testdata/maze followed by the ending of the format and .golden.
+// After changing how mazes are drawn, go test -update writes them anew.
+func TestRendersGolden(t *testing.T) {
+	walls := gen.GrowingTree(rand.New(rand.NewSource(1)), 8, 6)
+	m := mazetest.MustNew(mazelib.Layout{Width: 8, Height: 6, Walls: walls, Treasure: mazelib.Coordinate{X: 7, Y: 5}})
+	renders, err := mazelib.Renders(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range renders {
+		file := filepath.Join("testdata", "maze"+r.Ext+".golden")
+		if *update {
+			if err := ioutil.WriteFile(file, r.Data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(r.Data, want) {
+			t.Errorf("%s differs from what is drawn now, go test -update rewrites it", file)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+// Grid is how the rooms of a maze lie next to each other: which ways lead
+// out of a room, where they go and which rooms there are. Code walking
+// through a maze by its grid works for any shape of maze.
+type Grid interface {
+	// the ways out of every room, in the order they are tried
+	Directions() []Direction
+	// the room reached going from c in the direction, false if the way
+	// leads out of the maze
+	Neighbor(c Coordinate, d Direction) (Coordinate, bool)
+	// tells whether the room at c is part of the maze
+	Contains(c Coordinate) bool
+}
+
+// Rect is the grid of a maze of rows of rooms, the same width each, with
+// walls all around
+type Rect struct {
+	Width, Height int
+}
+
+func (r Rect) Directions() []Direction {
+	return Directions
+}
+
+func (r Rect) Neighbor(c Coordinate, d Direction) (Coordinate, bool) {
+	n := c.Move(d)
+	return n, r.Contains(n)
+}
+
+func (r Rect) Contains(c Coordinate) bool {
+	return c.In(r.Width, r.Height)
+}
+
+// GridOf returns the grid of the maze. Mazes of another shape than Rect tell
+// theirs with a Grid method.
+func GridOf(m MazeI) Grid {
+	if g, ok := m.(interface{ Grid() Grid }); ok {
+		return g.Grid()
+	}
+	return Rect{m.Width(), m.Height()}
+}
@@ -0,0 +1,28 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+// Package labyrinthpb holds the gRPC definition of the laybrinth protocol,
+// for clients written in other languages and for low latency benchmarks.
+//
+// The Go code is generated from labyrinth.proto with protoc and the
+// protoc-gen-go and protoc-gen-go-grpc plugins:
+//
+//	go generate ./mazelib/labyrinthpb
+//
+// Daedalus serves it with --grpc-addr and icarus speaks it with
+// --transport grpc.
+package labyrinthpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative labyrinth.proto
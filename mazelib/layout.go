@@ -0,0 +1,105 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import "fmt"
+
+// Layout is a maze the way it is saved, loaded and compared: its size, the
+// walls of every room by row and where the start and the treasure are.
+// As JSON it is the same as the mazes of the traces daedalus writes, so
+// those load as layouts too.
+type Layout struct {
+	Width    int        `json:"width"`
+	Height   int        `json:"height"`
+	Walls    [][]Survey `json:"walls"`
+	Start    Coordinate `json:"start"`
+	Treasure Coordinate `json:"treasure"`
+}
+
+// LayoutOf returns the layout of the maze
+func LayoutOf(m MazeI) (Layout, error) {
+	l := Layout{Width: m.Width(), Height: m.Height(), Walls: make([][]Survey, m.Height())}
+	for y := range l.Walls {
+		l.Walls[y] = make([]Survey, l.Width)
+		for x := range l.Walls[y] {
+			r, err := m.GetRoom(x, y)
+			if err != nil {
+				return l, err
+			}
+			l.Walls[y][x] = r.Walls
+			if r.Start {
+				l.Start = Coordinate{x, y}
+			}
+			if r.Treasure {
+				l.Treasure = Coordinate{x, y}
+			}
+		}
+	}
+	return l, nil
+}
+
+// Whole checks the layout has the walls of every room of the maze
+func (l Layout) Whole() error {
+	if l.Width < 1 || l.Height < 1 || len(l.Walls) != l.Height {
+		return fmt.Errorf("doesn't hold a whole %dx%d maze", l.Width, l.Height)
+	}
+	for y, row := range l.Walls {
+		if len(row) != l.Width {
+			return fmt.Errorf("doesn't hold a whole %dx%d maze, row %d has %d rooms", l.Width, l.Height, y, len(row))
+		}
+	}
+	return nil
+}
+
+// Placed checks the start and the treasure are two different rooms of the maze
+func (l Layout) Placed() error {
+	for _, c := range []Coordinate{l.Start, l.Treasure} {
+		if !c.In(l.Width, l.Height) {
+			return fmt.Errorf("has its start or treasure outside of the maze")
+		}
+	}
+	if l.Start == l.Treasure {
+		return fmt.Errorf("has the treasure at the start")
+	}
+	return nil
+}
+
+// Build lays the layout out in m, a maze of the same size without a start
+// or treasure yet, which Icarus awakes at the start of
+func (l Layout) Build(m MazeI) error {
+	if err := l.Whole(); err != nil {
+		return fmt.Errorf("maze %v", err)
+	}
+	if err := l.Placed(); err != nil {
+		return fmt.Errorf("maze %v", err)
+	}
+	if m.Width() != l.Width || m.Height() != l.Height {
+		return fmt.Errorf("can't lay a %dx%d maze out in one of %dx%d", l.Width, l.Height, m.Width(), m.Height())
+	}
+	for y, row := range l.Walls {
+		for x, s := range row {
+			r, err := m.GetRoom(x, y)
+			if err != nil {
+				return err
+			}
+			r.Walls = s
+		}
+	}
+	if err := m.SetTreasure(l.Treasure.X, l.Treasure.Y); err != nil {
+		return err
+	}
+	return m.SetStartPoint(l.Start.X, l.Start.Y)
+}
@@ -0,0 +1,39 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+// LongestPath returns the way between the two rooms of the maze furthest
+// apart, both included, the longest shortest way there is in it. Walking from any room to the furthest one from it and from there
+// to the furthest one again finds them in mazes without loops, in mazes
+// with loops the way found may be a little shorter than the longest.
+func LongestPath(m MazeI) []Coordinate {
+	a := furthest(DistanceMap(m, Coordinate{}))
+	dist := DistanceMap(m, a)
+	return PathTo(m, dist, furthest(dist))
+}
+
+// Returns the room furthest away in the distance map, the first one of them by row
+func furthest(dist [][]int) Coordinate {
+	var best Coordinate
+	for y, row := range dist {
+		for x, d := range row {
+			if d > dist[best.Y][best.X] {
+				best = Coordinate{x, y}
+			}
+		}
+	}
+	return best
+}
@@ -21,10 +21,17 @@ package mazelib
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
+	"time"
 )
 
+// ProtocolVersion is the newest version of the API between daedalus and
+// icarus, served under /v2. Servers keep speaking the older versions too.
+const ProtocolVersion = 2
+
 // Coordinate describes a location in the maze
 type Coordinate struct {
 	X int `json:"x"`
@@ -37,6 +44,90 @@ type Reply struct {
 	Victory bool   `json:"victory"`
 	Message string `json:"message"`
 	Error   bool   `json:"error"`
+	// Servers supporting sessions hand out an id on awake, which has to be
+	// sent along with every following request of the session.
+	Session string `json:"session,omitempty"`
+	// Tells clients what went wrong if Error is set, one of the ErrCode constants
+	ErrorCode string `json:"error_code,omitempty"`
+	// the moves the server refused in the maze, sent along with the victory
+	WallBumps int `json:"wall_bumps,omitempty"`
+	// The number of the maze within the session, the steps Icarus has taken
+	// in it so far and how many moves the server has been asked for in it,
+	// counting this one. Clients can tell dropped or repeated moves by
+	// MoveNumber not going up by one.
+	MazeID     int `json:"maze_id,omitempty"`
+	StepsTaken int `json:"steps_taken,omitempty"`
+	MoveNumber int `json:"move_number,omitempty"`
+}
+
+// Error codes a Reply can carry
+const (
+	// Icarus tried to walk through a wall
+	ErrCodeWallHit = "WALL_HIT"
+	// Icarus tried to walk out of the maze
+	ErrCodeOutOfBounds = "OUT_OF_BOUNDS"
+	// there is no maze being solved, Icarus has to awake first
+	ErrCodeNoActiveMaze = "NO_ACTIVE_MAZE"
+	// the direction is not one of up, down, left and right
+	ErrCodeInvalidDirection = "INVALID_DIRECTION"
+	// Icarus took too many steps and has to awake in a new maze
+	ErrCodeStepLimit = "STEP_LIMIT"
+	// Icarus took too long and has to awake in a new maze
+	ErrCodeTimeLimit = "TIME_LIMIT"
+)
+
+// ReplyError is an error the server reported in a Reply
+type ReplyError struct {
+	Code    string
+	Message string
+}
+
+func (e *ReplyError) Error() string {
+	return e.Message
+}
+
+// Is makes errors.Is tell the errors of mazelib by the code the server sent
+// along, so clients can check for ErrWall and the others like servers do
+func (e *ReplyError) Is(target error) bool {
+	return e.Code != "" && ErrorCode(target) == e.Code
+}
+
+// Errors of walking through mazes, which the server tells clients by their codes
+var (
+	ErrWall        = errors.New("Can't walk through walls")
+	ErrOutOfBounds = errors.New("room outside of maze boundaries")
+	ErrNoMaze      = errors.New("no maze to solve, call /awake first")
+)
+
+// ErrorCode returns the code the server tells clients err with, or "" for
+// errors without one
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrWall):
+		return ErrCodeWallHit
+	case errors.Is(err, ErrOutOfBounds):
+		return ErrCodeOutOfBounds
+	case errors.Is(err, ErrNoMaze):
+		return ErrCodeNoActiveMaze
+	}
+	return ""
+}
+
+// Results of a session, sent by the server once the session is done
+type Results struct {
+	Mazes        int `json:"mazes"`
+	AverageSteps int `json:"average_steps"`
+	// the score by the rules of the challenge, see Scoring
+	Score int `json:"score"`
+	// how long a maze took on average, if the server scores by time
+	AverageTime time.Duration `json:"average_time,omitempty"`
+}
+
+// MoveRequest is sent by clients streaming their moves, e.g. over a websocket.
+// The directions are walked one after the other and answered with a list of
+// replies, just like a request to /batch.
+type MoveRequest struct {
+	Directions []string `json:"directions"`
 }
 
 // Survey Given a location, survey surrounding locations
@@ -48,13 +139,6 @@ type Survey struct {
 	Left   bool `json:"left"`
 }
 
-const (
-	N = 1
-	S = 2
-	E = 3
-	W = 4
-)
-
 var ErrVictory error = errors.New("Victory")
 
 // Room contains the minimum informaion about a room in the maze.
@@ -65,7 +149,7 @@ type Room struct {
 	Walls    Survey
 }
 
-func (r *Room) AddWall(dir int) {
+func (r *Room) AddWall(dir Direction) {
 	switch dir {
 	case N:
 		r.Walls.Top = true
@@ -78,7 +162,7 @@ func (r *Room) AddWall(dir int) {
 	}
 }
 
-func (r *Room) RmWall(dir int) {
+func (r *Room) RmWall(dir Direction) {
 	switch dir {
 	case N:
 		r.Walls.Top = false
@@ -120,66 +204,156 @@ func AvgScores(in []int) int {
 	return total / (len(in))
 }
 
+// MedianScores returns the median of the scores, 0 without any
+func MedianScores(in []int) int {
+	if len(in) == 0 {
+		return 0
+	}
+
+	sorted := append([]int(nil), in...)
+	sort.Ints(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
 // PrintMaze : Function to Print Maze to Console
 func PrintMaze(m MazeI) {
-	fmt.Println("_" + strings.Repeat("___", m.Width()))
+	if err := FprintMaze(os.Stdout, m); err != nil {
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+}
+
+// SprintMaze draws the maze the way PrintMaze does and returns the drawing
+func SprintMaze(m MazeI) string {
+	var b strings.Builder
+	if err := FprintMaze(&b, m); err != nil {
+		return err.Error()
+	}
+	return b.String()
+}
+
+// FprintMaze draws the maze the way PrintMaze does to w.
+// Returns the error of the first room that can't be surveyed or write that
+// fails, after the rows drawn before it.
+func FprintMaze(w io.Writer, m MazeI) error {
+	return fprintMaze(w, m, plainRoom)
+}
+
+// roomDrawing draws the room at c given what it looks like without anything
+// in it, which is spaces or a floor of underscores if there is a wall below
+type roomDrawing func(c Coordinate, r *Room, empty string) string
+
+// Draws the treasure as ⏃ and the start as ⏀, or ⏅ and ⏂ with a wall below
+func plainRoom(c Coordinate, r *Room, empty string) string {
+	walled := strings.HasPrefix(empty, "_")
+	switch {
+	case r.Treasure && walled:
+		return "⏅" + empty[1:]
+	case r.Treasure:
+		return "⏃" + empty[1:]
+	case r.Start && walled:
+		return "⏂" + empty[1:]
+	case r.Start:
+		return "⏀" + empty[1:]
+	}
+	return empty
+}
+
+// Draws the maze like PrintMaze does, with the rooms drawn by draw
+func fprintMaze(w io.Writer, m MazeI, draw roomDrawing) error {
+	// the walls on top, each running on into the corner right of it
+	var top strings.Builder
+	for x := 0; x < m.Width(); x++ {
+		s, err := m.Discover(x, 0)
+		if err != nil {
+			return err
+		}
+		if x == 0 && s.Top {
+			top.WriteString("_")
+		} else if x == 0 {
+			top.WriteString(" ")
+		}
+		if s.Top {
+			top.WriteString("___")
+		} else {
+			top.WriteString("   ")
+		}
+	}
+	if _, err := fmt.Fprintln(w, top.String()); err != nil {
+		return err
+	}
+	var str strings.Builder
 	for y := 0; y < m.Height(); y++ {
-		str := ""
+		str.Reset()
 		for x := 0; x < m.Width(); x++ {
-			if x == 0 {
-				str += "|"
-			}
 			r, err := m.GetRoom(x, y)
 			if err != nil {
-				fmt.Println(err)
-				os.Exit(-1)
+				return err
 			}
 			s, err := m.Discover(x, y)
 			if err != nil {
-				fmt.Println(err)
-				os.Exit(-1)
+				return err
+			}
+			if x == 0 && s.Left {
+				str.WriteString("|")
+			} else if x == 0 {
+				str.WriteString(" ")
 			}
 			if s.Bottom {
-				if r.Treasure {
-					str += "⏅_"
-				} else if r.Start {
-					str += "⏂_"
-				} else {
-					str += "__"
-				}
+				str.WriteString(draw(Coordinate{x, y}, r, "__"))
 			} else {
-				if r.Treasure {
-					str += "⏃ "
-				} else if r.Start {
-					str += "⏀ "
-				} else {
-					str += "  "
-				}
+				str.WriteString(draw(Coordinate{x, y}, r, "  "))
 			}
 
 			if s.Right {
-				str += "|"
+				str.WriteString("|")
 			} else {
-				str += "_"
+				str.WriteString("_")
 			}
 
 		}
-		fmt.Println(str)
+		if _, err := fmt.Fprintln(w, str.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Move returns the coordinate of the room a step in the direction leads to
+func (c Coordinate) Move(dir Direction) Coordinate {
+	dx, dy := dir.Delta()
+	return Coordinate{c.X + dx, c.Y + dy}
+}
 
+// Neighbors returns the coordinates of the four rooms next to c, in the
+// order of Directions. Some may lie outside of the maze, see In.
+func (c Coordinate) Neighbors() []Coordinate {
+	n := make([]Coordinate, len(Directions))
+	for i, d := range Directions {
+		n[i] = c.Move(d)
 	}
+	return n
 }
-func (c *Coordinate) Dir(dir string) Coordinate {
-	switch dir {
-	case "up":
-		return Coordinate{c.X, c.Y - 1}
-	case "down":
-		return Coordinate{c.X, c.Y + 1}
-	case "left":
-		return Coordinate{c.X - 1, c.Y}
-	case "right":
-		return Coordinate{c.X + 1, c.Y}
-	}
-	return Coordinate{}
+
+// ManhattanDist returns the number of steps between c and other in a maze without walls
+func (c Coordinate) ManhattanDist(other Coordinate) int {
+	dx, dy := c.X-other.X, c.Y-other.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx + dy
+}
+
+// In tells whether c lies within a maze of the given width and height
+func (c Coordinate) In(width, height int) bool {
+	return c.X >= 0 && c.Y >= 0 && c.X < width && c.Y < height
 }
 
 func (c *Coordinate) IsNil() bool {
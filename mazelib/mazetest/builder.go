@@ -0,0 +1,135 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazetest
+
+import (
+	"fmt"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Builder lays out a maze, starting from one with all walls up and Icarus
+// meant to awake at the top left and find the treasure at the bottom right.
+// Its methods panic on rooms outside of the maze, the way a mistake in a
+// test should.
+type Builder struct {
+	l mazelib.Layout
+}
+
+// NewBuilder returns a builder of a maze of the size
+func NewBuilder(width, height int) *Builder {
+	if width < 1 || height < 1 {
+		panic(fmt.Sprintf("mazetest: can't build a %dx%d maze", width, height))
+	}
+	walls := make([][]mazelib.Survey, height)
+	for y := range walls {
+		walls[y] = make([]mazelib.Survey, width)
+		for x := range walls[y] {
+			walls[y][x] = mazelib.Survey{Top: true, Right: true, Bottom: true, Left: true}
+		}
+	}
+	return &Builder{l: mazelib.Layout{
+		Width:    width,
+		Height:   height,
+		Walls:    walls,
+		Treasure: mazelib.Coordinate{X: width - 1, Y: height - 1},
+	}}
+}
+
+// Returns the walls of the room at c
+func (b *Builder) room(c mazelib.Coordinate) *mazelib.Survey {
+	if !c.In(b.l.Width, b.l.Height) {
+		panic(fmt.Sprintf("mazetest: room %d,%d is outside of the %dx%d maze", c.X, c.Y, b.l.Width, b.l.Height))
+	}
+	return &b.l.Walls[c.Y][c.X]
+}
+
+// Puts the wall to the direction of the room at c up or takes it down,
+// from both sides unless it is on the outside of the maze
+func (b *Builder) wall(c mazelib.Coordinate, d mazelib.Direction, up bool) {
+	set := func(s *mazelib.Survey, d mazelib.Direction) {
+		r := mazelib.Room{Walls: *s}
+		if up {
+			r.AddWall(d)
+		} else {
+			r.RmWall(d)
+		}
+		*s = r.Walls
+	}
+	set(b.room(c), d)
+	if n := c.Move(d); n.In(b.l.Width, b.l.Height) {
+		set(b.room(n), d.Opposite())
+	}
+}
+
+// Open takes down the walls to the directions of the room at x,y.
+// Walls on the outside of the maze can be opened too, for mazes which are
+// broken on purpose.
+func (b *Builder) Open(x, y int, dirs ...mazelib.Direction) *Builder {
+	for _, d := range dirs {
+		b.wall(mazelib.Coordinate{X: x, Y: y}, d, false)
+	}
+	return b
+}
+
+// Close puts the walls to the directions of the room at x,y back up
+func (b *Builder) Close(x, y int, dirs ...mazelib.Direction) *Builder {
+	for _, d := range dirs {
+		b.wall(mazelib.Coordinate{X: x, Y: y}, d, true)
+	}
+	return b
+}
+
+// Walk opens a way from the room at x,y along the route
+func (b *Builder) Walk(x, y int, route ...mazelib.Direction) *Builder {
+	c := mazelib.Coordinate{X: x, Y: y}
+	for _, d := range route {
+		b.wall(c, d, false)
+		c = c.Move(d)
+		b.room(c)
+	}
+	return b
+}
+
+// Start has Icarus awake at x,y
+func (b *Builder) Start(x, y int) *Builder {
+	b.room(mazelib.Coordinate{X: x, Y: y})
+	b.l.Start = mazelib.Coordinate{X: x, Y: y}
+	return b
+}
+
+// Treasure puts the treasure at x,y
+func (b *Builder) Treasure(x, y int) *Builder {
+	b.room(mazelib.Coordinate{X: x, Y: y})
+	b.l.Treasure = mazelib.Coordinate{X: x, Y: y}
+	return b
+}
+
+// Layout returns the layout built so far
+func (b *Builder) Layout() mazelib.Layout {
+	l := b.l
+	l.Walls = make([][]mazelib.Survey, len(b.l.Walls))
+	for y, row := range b.l.Walls {
+		l.Walls[y] = append([]mazelib.Survey(nil), row...)
+	}
+	return l
+}
+
+// Maze returns the maze built, and panics if the start and the treasure
+// are in the same room
+func (b *Builder) Maze() *Maze {
+	return MustNew(b.Layout())
+}
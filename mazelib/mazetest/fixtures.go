@@ -0,0 +1,109 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazetest
+
+import (
+	"fmt"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Corridor returns a single row of length rooms with the start at its left
+// end and the treasure at its right one, length-1 steps away
+func Corridor(length int) *Maze {
+	if length < 2 {
+		panic(fmt.Sprintf("mazetest: a corridor needs at least 2 rooms, not %d", length))
+	}
+	route := make([]mazelib.Direction, length-1)
+	for i := range route {
+		route[i] = mazelib.E
+	}
+	return NewBuilder(length, 1).Walk(0, 0, route...).Maze()
+}
+
+// Spiral returns a size by size maze which is a single way winding clockwise
+// from the start at the top left to the treasure in the middle, through
+// every room. The treasure is size*size-1 steps away.
+func Spiral(size int) *Maze {
+	if size < 2 {
+		panic(fmt.Sprintf("mazetest: a spiral needs to be at least 2 rooms wide, not %d", size))
+	}
+	// the legs of the spiral get shorter every second turn
+	legs := []int{size - 1}
+	for n := size - 1; n > 0; n-- {
+		legs = append(legs, n, n)
+	}
+	var route []mazelib.Direction
+	c, d := mazelib.Coordinate{}, mazelib.E
+	for _, n := range legs {
+		for i := 0; i < n; i++ {
+			route = append(route, d)
+			c = c.Move(d)
+		}
+		d = d.Clockwise()
+	}
+	return NewBuilder(size, size).Walk(0, 0, route...).Treasure(c.X, c.Y).Maze()
+}
+
+// Comb returns a maze of dead ends: a corridor along the top with a tooth
+// of depth rooms hanging down from each of its rooms. The treasure is at the
+// bottom of the last tooth, teeth-1+depth steps from the start at the top
+// left, and every other tooth is a dead end.
+func Comb(teeth, depth int) *Maze {
+	if teeth < 2 || depth < 1 {
+		panic(fmt.Sprintf("mazetest: a comb needs at least 2 teeth of 1 room, not %d of %d", teeth, depth))
+	}
+	b := NewBuilder(teeth, depth+1)
+	for x := 0; x < teeth; x++ {
+		if x < teeth-1 {
+			b.Open(x, 0, mazelib.E)
+		}
+		tooth := make([]mazelib.Direction, depth)
+		for i := range tooth {
+			tooth[i] = mazelib.S
+		}
+		b.Walk(x, 0, tooth...)
+	}
+	return b.Maze()
+}
+
+// Braided returns a maze without any dead ends: every row is a corridor,
+// joined to the next one at both of its ends, so every two rows make a loop.
+// The treasure at the bottom right is width+height-2 steps from the start
+// at the top left.
+func Braided(width, height int) *Maze {
+	if width < 2 || height < 2 {
+		panic(fmt.Sprintf("mazetest: a braided maze needs to be at least 2x2, not %dx%d", width, height))
+	}
+	b := NewBuilder(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			b.Open(x, y, mazelib.E)
+		}
+		if y < height-1 {
+			b.Open(0, y, mazelib.S).Open(width-1, y, mazelib.S)
+		}
+	}
+	return b.Maze()
+}
+
+// SingleRoom returns a maze of a single room, walled in all around, which
+// Icarus awakes in. There is no treasure, as there is nowhere to put it.
+func SingleRoom() *Maze {
+	m := &Maze{width: 1, rooms: []mazelib.Room{{Walls: mazelib.Survey{Top: true, Right: true, Bottom: true, Left: true}}}}
+	m.SetStartPoint(0, 0)
+	return m
+}
@@ -0,0 +1,189 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+// Package mazetest has mazes of known layouts to try solvers, renderers and
+// metrics on, and a Builder to lay out more. The mazes are mazelib.MazeIs
+// of this process, which solve.MazeMover walks Icarus through.
+package mazetest
+
+import (
+	"bytes"
+	"errors"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Maze is a maze laid out for a test. Icarus awakes at the start, and
+// finds the treasure the way he would on daedalus.
+type Maze struct {
+	width    int
+	rooms    []mazelib.Room
+	start    mazelib.Coordinate
+	icarus   mazelib.Coordinate
+	treasure *mazelib.Coordinate
+	// the steps Icarus took and the moves the maze refused
+	Steps int
+	Bumps int
+}
+
+// New returns a maze laid out like l
+func New(l mazelib.Layout) (*Maze, error) {
+	if err := l.Whole(); err != nil {
+		return nil, err
+	}
+	m := &Maze{width: l.Width, rooms: make([]mazelib.Room, l.Width*l.Height)}
+	if err := l.Build(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MustNew is like New but panics if the layout isn't a maze
+func MustNew(l mazelib.Layout) *Maze {
+	m, err := New(l)
+	if err != nil {
+		panic("mazetest: " + err.Error())
+	}
+	return m
+}
+
+// Parse returns the maze written as text the way mazelib.ParseMaze reads it
+func Parse(text string) (*Maze, error) {
+	l, err := mazelib.ParseMaze(bytes.NewBufferString(text))
+	if err != nil {
+		return nil, err
+	}
+	return New(l)
+}
+
+// MustParse is like Parse but panics if the text isn't a maze, for mazes
+// drawn in the tests themselves
+func MustParse(text string) *Maze {
+	m, err := Parse(text)
+	if err != nil {
+		panic("mazetest: " + err.Error())
+	}
+	return m
+}
+
+// Layout returns the layout of the maze
+func (m *Maze) Layout() mazelib.Layout {
+	l, _ := mazelib.LayoutOf(m)
+	return l
+}
+
+// Reset puts Icarus back at the start, for the next solver to try the maze
+func (m *Maze) Reset() {
+	m.icarus = m.start
+	m.Steps, m.Bumps = 0, 0
+}
+
+// Rooms returns the number of rooms of the maze
+func (m *Maze) Rooms() int { return len(m.rooms) }
+
+func (m *Maze) GetRoom(x, y int) (*mazelib.Room, error) {
+	if !(mazelib.Coordinate{X: x, Y: y}).In(m.Width(), m.Height()) {
+		return &mazelib.Room{}, mazelib.ErrOutOfBounds
+	}
+	return &m.rooms[y*m.width+x], nil
+}
+
+func (m *Maze) Width() int { return m.width }
+
+func (m *Maze) Height() int {
+	if m.width == 0 {
+		return 0
+	}
+	return len(m.rooms) / m.width
+}
+
+func (m *Maze) SetStartPoint(x, y int) error {
+	r, err := m.GetRoom(x, y)
+	if err != nil {
+		return err
+	}
+	if r.Treasure {
+		return errors.New("can't start in the treasure")
+	}
+	if s, err := m.GetRoom(m.start.X, m.start.Y); err == nil {
+		s.Start = false
+	}
+	r.Start = true
+	m.start = mazelib.Coordinate{X: x, Y: y}
+	m.icarus = m.start
+	return nil
+}
+
+func (m *Maze) SetTreasure(x, y int) error {
+	r, err := m.GetRoom(x, y)
+	if err != nil {
+		return err
+	}
+	if r.Start {
+		return errors.New("can't have the treasure at the start")
+	}
+	if m.treasure != nil {
+		m.rooms[m.treasure.Y*m.width+m.treasure.X].Treasure = false
+	}
+	r.Treasure = true
+	m.treasure = &mazelib.Coordinate{X: x, Y: y}
+	return nil
+}
+
+// LookAround returns the walls of the room Icarus is in, or
+// mazelib.ErrVictory if he is at the treasure
+func (m *Maze) LookAround() (mazelib.Survey, error) {
+	if m.treasure != nil && *m.treasure == m.icarus {
+		return mazelib.Survey{}, mazelib.ErrVictory
+	}
+	return m.Discover(m.icarus.X, m.icarus.Y)
+}
+
+// Discover returns the walls of the room at x,y
+func (m *Maze) Discover(x, y int) (mazelib.Survey, error) {
+	r, err := m.GetRoom(x, y)
+	if err != nil {
+		return mazelib.Survey{}, err
+	}
+	return r.Walls, nil
+}
+
+func (m *Maze) Icarus() (x, y int) { return m.icarus.X, m.icarus.Y }
+
+func (m *Maze) MoveLeft() error  { return m.move(mazelib.W) }
+func (m *Maze) MoveRight() error { return m.move(mazelib.E) }
+func (m *Maze) MoveUp() error    { return m.move(mazelib.N) }
+func (m *Maze) MoveDown() error  { return m.move(mazelib.S) }
+
+// Moves Icarus a step in the direction, unless there is a wall or the edge
+// of the maze in the way
+func (m *Maze) move(d mazelib.Direction) error {
+	s, err := m.LookAround()
+	if err != nil {
+		return err
+	}
+	n := m.icarus.Move(d)
+	switch {
+	case s.HasExit(d) && n.In(m.Width(), m.Height()):
+		m.icarus = n
+		m.Steps++
+		return nil
+	case s.HasExit(d):
+		m.Bumps++
+		return mazelib.ErrOutOfBounds
+	}
+	m.Bumps++
+	return mazelib.ErrWall
+}
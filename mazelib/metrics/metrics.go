@@ -0,0 +1,151 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+// Package metrics measures how a maze is laid out and how hard it is to
+// solve, the same way for mazes of any generator.
+package metrics
+
+import "bitbucket.org/mannih/gc6/mazelib"
+
+// Metrics are what Measure found out about a maze
+type Metrics struct {
+	Rooms int `json:"rooms"`
+	// rooms with a single way out
+	DeadEnds int `json:"dead_ends"`
+	// how many rooms have no, one, two, three and four ways out
+	Exits [5]int `json:"exits"`
+	// steps of the shortest way from the start to the treasure, -1 if there is none
+	SolutionLength int `json:"solution_length"`
+	// passages which could be walled up without cutting off any room
+	Loops int `json:"loops"`
+	// from 0 to 100, see Measure
+	Difficulty float64 `json:"difficulty"`
+}
+
+// Measure returns the metrics of the maze.
+// The difficulty is made up of how much of the maze the way to the treasure
+// runs through, for half of it, how many of the rooms are dead ends or
+// junctions Icarus has to choose at, for 30, and how few loops there are to
+// get back on the way through, for 20.
+func Measure(m mazelib.MazeI) Metrics {
+	w, h := m.Width(), m.Height()
+	mt := Metrics{Rooms: w * h, SolutionLength: -1}
+
+	var start, treasure mazelib.Coordinate
+	passages := 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := mazelib.Coordinate{X: x, Y: y}
+			if r, err := m.GetRoom(x, y); err == nil {
+				if r.Start {
+					start = c
+				}
+				if r.Treasure {
+					treasure = c
+				}
+			}
+			exits := 0
+			for _, d := range mazelib.Directions {
+				if open(m, c, d) {
+					exits++
+				}
+			}
+			mt.Exits[exits]++
+			// every passage is counted from the room left or above it
+			if open(m, c, mazelib.E) {
+				passages++
+			}
+			if open(m, c, mazelib.S) {
+				passages++
+			}
+		}
+	}
+	mt.DeadEnds = mt.Exits[1]
+	mt.Loops = passages - mt.Rooms + components(m)
+
+	dist := mazelib.DistanceMap(m, start)
+	if treasure.In(w, h) {
+		mt.SolutionLength = dist[treasure.Y][treasure.X]
+	}
+
+	route := 0.0
+	if mt.SolutionLength > 0 && mt.Rooms > 1 {
+		route = float64(mt.SolutionLength) / float64(mt.Rooms-1)
+	}
+	choices := float64(mt.DeadEnds+mt.Exits[3]+mt.Exits[4]) / float64(mt.Rooms)
+	// about one room in ten in a loop makes a maze a lot easier
+	loops := 1 / (1 + 10*float64(mt.Loops)/float64(mt.Rooms))
+	mt.Difficulty = 100 * (0.5*clamp(route) + 0.3*clamp(choices) + 0.2*loops)
+	return mt
+}
+
+// Tells whether there is a way from the room at c in the direction,
+// into another room of the maze
+func open(m mazelib.MazeI, c mazelib.Coordinate, d mazelib.Direction) bool {
+	if !c.Move(d).In(m.Width(), m.Height()) {
+		return false
+	}
+	s, err := m.Discover(c.X, c.Y)
+	if err != nil {
+		return false
+	}
+	return s.HasExit(d)
+}
+
+// Returns the number of parts of the maze that can't be reached from each other
+func components(m mazelib.MazeI) int {
+	seen := make([][]bool, m.Height())
+	for y := range seen {
+		seen[y] = make([]bool, m.Width())
+	}
+	// flood every part from its first room, with a single queue and seen
+	// grid for all of them
+	n := 0
+	var queue []mazelib.Coordinate
+	for y := range seen {
+		for x := range seen[y] {
+			if seen[y][x] {
+				continue
+			}
+			n++
+			seen[y][x] = true
+			queue = append(queue[:0], mazelib.Coordinate{X: x, Y: y})
+			for len(queue) > 0 {
+				c := queue[len(queue)-1]
+				queue = queue[:len(queue)-1]
+				for _, d := range mazelib.Directions {
+					r := c.Move(d)
+					if !open(m, c, d) || seen[r.Y][r.X] {
+						continue
+					}
+					seen[r.Y][r.X] = true
+					queue = append(queue, r)
+				}
+			}
+		}
+	}
+	return n
+}
+
+// Keeps a share between 0 and 1
+func clamp(f float64) float64 {
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
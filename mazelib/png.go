@@ -0,0 +1,148 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// RenderPNG draws the maze as a PNG image, the way RenderSVG does
+func RenderPNG(m MazeI, opts RenderOptions) ([]byte, error) {
+	img, err := RenderImage(m, opts)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// RenderImage draws the maze the way RenderSVG does, as an image to encode
+// in any format
+func RenderImage(m MazeI, opts RenderOptions) (*image.RGBA, error) {
+	opts = opts.withDefaults()
+	p := opts.Palette
+	c := canvas{image.NewRGBA(image.Rect(0, 0, m.Width()*opts.RoomSize+1, m.Height()*opts.RoomSize+1)), opts.RoomSize}
+	bounds := c.img.Bounds()
+	c.fill(0, 0, bounds.Dx(), bounds.Dy(), p.Background)
+
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			r, err := m.GetRoom(x, y)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case r.Treasure:
+				c.room(Coordinate{x, y}, p.Treasure)
+			case r.Start:
+				c.room(Coordinate{x, y}, p.Start)
+			}
+		}
+	}
+
+	for _, o := range opts.Overlays {
+		for i := 1; i < len(o.Path); i++ {
+			c.segment(o.Path[i-1], o.Path[i], o.Color)
+		}
+	}
+
+	size := opts.RoomSize
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			s, err := m.Discover(x, y)
+			if err != nil {
+				return nil, err
+			}
+			x0, y0, x1, y1 := x*size, y*size, (x+1)*size, (y+1)*size
+			if s.Top {
+				c.fill(x0, y0, x1+1, y0+1, p.Wall)
+			}
+			if s.Left {
+				c.fill(x0, y0, x0+1, y1+1, p.Wall)
+			}
+			if s.Bottom {
+				c.fill(x0, y1, x1+1, y1+1, p.Wall)
+			}
+			if s.Right {
+				c.fill(x1, y0, x1+1, y1+1, p.Wall)
+			}
+		}
+	}
+
+	if opts.Icarus {
+		x, y := m.Icarus()
+		c.dot(Coordinate{x, y}, p.Icarus)
+	}
+	return c.img, nil
+}
+
+// canvas is an image being drawn on, with rooms of size pixels
+type canvas struct {
+	img  *image.RGBA
+	size int
+}
+
+// Fills the rectangle from x0, y0 up to x1, y1
+func (c canvas) fill(x0, y0, x1, y1 int, col color.RGBA) {
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			c.img.SetRGBA(x, y, col)
+		}
+	}
+}
+
+// Fills the room inside its walls
+func (c canvas) room(r Coordinate, col color.RGBA) {
+	c.fill(r.X*c.size+1, r.Y*c.size+1, (r.X+1)*c.size, (r.Y+1)*c.size, col)
+}
+
+// Returns the middle of the room
+func (c canvas) mid(r Coordinate) (int, int) {
+	return r.X*c.size + c.size/2, r.Y*c.size + c.size/2
+}
+
+// Draws a path from the middle of one room to the middle of the next
+func (c canvas) segment(from, to Coordinate, col color.RGBA) {
+	thick := c.size / 4
+	ax, ay := c.mid(from)
+	bx, by := c.mid(to)
+	if ax > bx {
+		ax, bx = bx, ax
+	}
+	if ay > by {
+		ay, by = by, ay
+	}
+	c.fill(ax-thick/2, ay-thick/2, bx+thick/2+1, by+thick/2+1, col)
+}
+
+// Draws a dot in the middle of the room, the way Icarus is drawn
+func (c canvas) dot(r Coordinate, col color.RGBA) {
+	cx, cy := c.mid(r)
+	radius := c.size / 3
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if dx*dx+dy*dy <= radius*radius {
+				c.img.SetRGBA(cx+dx, cy+dy, col)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"math"
+	"sort"
+)
+
+// Scoring follows the rules of the challenge:
+// Icarus is evaluated on a fixed number of mazes, and his score is the
+// average of the steps he took in each of them. A maze he didn't solve
+// within MaxSteps, or didn't play at all, counts as MaxSteps steps.
+// A maze solved in exactly MaxSteps still counts as solved, so unsolved mazes
+// are recorded as more steps than that.
+// MaxSteps has to be at least 1, or no maze would ever count as solved.
+// The lower the score the better.
+type Scoring struct {
+	// the number of mazes of an evaluation, 0 counts all mazes played
+	Mazes    int
+	MaxSteps int
+}
+
+// Score is the outcome of an evaluation
+type Score struct {
+	Mazes int `json:"mazes"`
+	// mazes solved within MaxSteps
+	Solved int `json:"solved"`
+	// mazes counted as MaxSteps, because they weren't solved or weren't played
+	Penalized int `json:"penalized"`
+	Final     int `json:"score"`
+}
+
+// Score evaluates the steps taken in each maze, in the order they were played.
+// Mazes beyond the number of an evaluation don't count.
+func (s Scoring) Score(steps []int) Score {
+	mazes := s.Mazes
+	if mazes == 0 {
+		mazes = len(steps)
+	}
+	if len(steps) > mazes {
+		steps = steps[:mazes]
+	}
+
+	score := Score{Mazes: mazes, Penalized: mazes - len(steps)}
+	counted := make([]int, 0, mazes)
+	for _, x := range steps {
+		if x > s.MaxSteps {
+			x = s.MaxSteps
+			score.Penalized++
+		} else {
+			score.Solved++
+		}
+		counted = append(counted, x)
+	}
+	for len(counted) < mazes {
+		counted = append(counted, s.MaxSteps)
+	}
+	score.Final = AvgScores(counted)
+	return score
+}
+
+// Stats describe how the steps taken in a number of mazes are distributed
+type Stats struct {
+	Count     int      `json:"count"`
+	Mean      int      `json:"mean"`
+	Median    int      `json:"median"`
+	P90       int      `json:"p90"`
+	P99       int      `json:"p99"`
+	StdDev    float64  `json:"stddev"`
+	Histogram []Bucket `json:"histogram"`
+}
+
+// Bucket counts the mazes which took From to To steps, both included
+type Bucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// Statistics describes the scores, with a histogram of the number of buckets asked for
+func Statistics(scores []int, buckets int) Stats {
+	st := Stats{Count: len(scores), Histogram: []Bucket{}}
+	if len(scores) == 0 {
+		return st
+	}
+
+	sorted := append([]int(nil), scores...)
+	sort.Ints(sorted)
+	st.Mean = AvgScores(sorted)
+	st.Median = MedianScores(sorted)
+	st.P90 = Percentile(sorted, 90)
+	st.P99 = Percentile(sorted, 99)
+
+	var sum, squares float64
+	for _, x := range sorted {
+		sum += float64(x)
+	}
+	mean := sum / float64(len(sorted))
+	for _, x := range sorted {
+		squares += (float64(x) - mean) * (float64(x) - mean)
+	}
+	st.StdDev = math.Sqrt(squares / float64(len(sorted)))
+
+	max := sorted[len(sorted)-1]
+	width := max/buckets + 1
+	for from := 0; from <= max; from += width {
+		st.Histogram = append(st.Histogram, Bucket{From: from, To: from + width - 1})
+	}
+	for _, x := range sorted {
+		st.Histogram[x/width].Count++
+	}
+	return st
+}
+
+// Percentile returns the score p percent of the scores are at most, by the nearest rank
+func Percentile(scores []int, p int) int {
+	if len(scores) == 0 {
+		return 0
+	}
+
+	sorted := append([]int(nil), scores...)
+	sort.Ints(sorted)
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
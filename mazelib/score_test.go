@@ -0,0 +1,51 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib_test
+
+import (
+	"testing"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		scoring mazelib.Scoring
+		steps   []int
+		want    mazelib.Score
+	}{
+		{"all solved", mazelib.Scoring{MaxSteps: 100}, []int{10, 20, 30}, mazelib.Score{Mazes: 3, Solved: 3, Final: 20}},
+		{"solved in exactly max-steps", mazelib.Scoring{MaxSteps: 100}, []int{100}, mazelib.Score{Mazes: 1, Solved: 1, Final: 100}},
+		// the way daedalus records a maze icarus didn't solve
+		{"unsolved", mazelib.Scoring{MaxSteps: 100}, []int{101, 50}, mazelib.Score{Mazes: 2, Solved: 1, Penalized: 1, Final: 75}},
+		// solved in 90 steps, with 3 walls bumped into at a wall-penalty of 5
+		{"wall bumps over max-steps", mazelib.Scoring{MaxSteps: 100}, []int{90 + 3*5}, mazelib.Score{Mazes: 1, Penalized: 1, Final: 100}},
+		{"wall bumps within max-steps", mazelib.Scoring{MaxSteps: 100}, []int{90 + 2*5}, mazelib.Score{Mazes: 1, Solved: 1, Final: 100}},
+		{"missing mazes", mazelib.Scoring{Mazes: 4, MaxSteps: 100}, []int{20, 40}, mazelib.Score{Mazes: 4, Solved: 2, Penalized: 2, Final: 65}},
+		{"mazes beyond the evaluation", mazelib.Scoring{Mazes: 2, MaxSteps: 100}, []int{20, 40, 101}, mazelib.Score{Mazes: 2, Solved: 2, Final: 30}},
+		// the smallest max-steps allowed, the treasure right next to the start
+		{"max-steps of 1", mazelib.Scoring{MaxSteps: 1}, []int{1, 2}, mazelib.Score{Mazes: 2, Solved: 1, Penalized: 1, Final: 1}},
+		{"nothing played", mazelib.Scoring{MaxSteps: 100}, nil, mazelib.Score{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scoring.Score(tt.steps); got != tt.want {
+				t.Errorf("Score(%v) = %+v, want %+v", tt.steps, got, tt.want)
+			}
+		})
+	}
+}
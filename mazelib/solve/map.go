@@ -0,0 +1,341 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+// Package solve is how Icarus finds his way through a laybrinth: the map he
+// keeps of the rooms he has seen, the strategies deciding where he goes next
+// and Solve, walking him through a maze until he finds the treasure.
+// Mazes are played through a Mover, which may be a maze of this process or
+// a daedalus far away.
+package solve
+
+import (
+	"sort"
+	"strings"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Map is what Icarus remembers about the laybrinth so far.
+// Icarus is never told where he is, so all coordinates are relative
+// to the room he woke up in.
+type Map struct {
+	rooms map[mazelib.Coordinate]mazelib.Survey
+	// rooms proven to be part of a dead end without anything left to explore
+	dead map[mazelib.Coordinate]bool
+	// rooms which may have become dead ends since the last filling, because
+	// they or a neighbor were surveyed, or Icarus left them
+	changed []mazelib.Coordinate
+}
+
+// NewMap returns the map of a maze Icarus hasn't seen anything of yet
+func NewMap() *Map {
+	return &Map{
+		rooms: make(map[mazelib.Coordinate]mazelib.Survey),
+		dead:  make(map[mazelib.Coordinate]bool),
+	}
+}
+
+// Record remembers the survey of a room
+func (m *Map) Record(c mazelib.Coordinate, s mazelib.Survey) {
+	m.rooms[c] = s
+	m.touch(c)
+}
+
+// Marks the room and its neighbors as to be looked at again by FillDeadEnds
+func (m *Map) touch(c mazelib.Coordinate) {
+	m.changed = append(m.changed, c)
+	for _, d := range mazelib.Directions {
+		m.changed = append(m.changed, c.Move(d))
+	}
+}
+
+// AddWall remembers a wall Icarus bumped into but didn't know about
+func (m *Map) AddWall(c mazelib.Coordinate, dir mazelib.Direction) {
+	s := m.rooms[c]
+	switch dir {
+	case mazelib.N:
+		s.Top = true
+	case mazelib.S:
+		s.Bottom = true
+	case mazelib.W:
+		s.Left = true
+	case mazelib.E:
+		s.Right = true
+	}
+	m.rooms[c] = s
+	m.touch(c)
+}
+
+// Known tells whether Icarus has been in the room
+func (m *Map) Known(c mazelib.Coordinate) bool {
+	_, ok := m.rooms[c]
+	return ok
+}
+
+// Center estimates the center of the maze.
+// Icarus doesn't know where he woke up, so the center of the rooms he
+// has seen so far is the best guess he has.
+func (m *Map) Center() (float64, float64) {
+	var minX, maxX, minY, maxY int
+	for c := range m.rooms {
+		if c.X < minX {
+			minX = c.X
+		}
+		if c.X > maxX {
+			maxX = c.X
+		}
+		if c.Y < minY {
+			minY = c.Y
+		}
+		if c.Y > maxY {
+			maxY = c.Y
+		}
+	}
+	return float64(minX+maxX) / 2, float64(minY+maxY) / 2
+}
+
+// Open returns true if there is no wall in the given direction of a known room
+func (m *Map) Open(c mazelib.Coordinate, dir mazelib.Direction) bool {
+	s, ok := m.rooms[c]
+	return ok && s.HasExit(dir)
+}
+
+// Unexplored returns the directions leading from a known room into rooms
+// Icarus hasn't seen yet
+func (m *Map) Unexplored(c mazelib.Coordinate) []mazelib.Direction {
+	var dirs []mazelib.Direction
+	for _, d := range mazelib.Directions {
+		if m.Open(c, d) && !m.Known(c.Move(d)) {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs
+}
+
+// FillDeadEnds runs dead-end filling over the known part of the maze.
+// A room is filled when nothing is left to explore from it and at most one
+// of its exits leads to a room that isn't filled yet. Filling a room goes on
+// with its neighbors, until whole corridors ending in dead ends are closed
+// off. Only the rooms surveyed since the last filling, their neighbors and
+// the rooms Icarus left are looked at, which is all that can have changed.
+// The room Icarus is standing in is never filled.
+func (m *Map) FillDeadEnds(pos mazelib.Coordinate) {
+	queue := m.changed
+	m.changed = nil
+	for len(queue) > 0 {
+		c := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if c == pos {
+			// looked at again once Icarus moves on
+			m.changed = append(m.changed, c)
+			continue
+		}
+		if !m.Known(c) || m.dead[c] || len(m.Unexplored(c)) > 0 {
+			continue
+		}
+		exits := 0
+		for _, d := range mazelib.Directions {
+			if m.Open(c, d) && !m.dead[c.Move(d)] {
+				exits++
+			}
+		}
+		if exits <= 1 {
+			m.dead[c] = true
+			for _, d := range mazelib.Directions {
+				queue = append(queue, c.Move(d))
+			}
+		}
+	}
+}
+
+// routes holds the shortest paths through known rooms from a single room.
+type routes struct {
+	dist map[mazelib.Coordinate]int
+	prev map[mazelib.Coordinate]mazelib.Coordinate
+	step map[mazelib.Coordinate]mazelib.Direction
+}
+
+// Runs a breadth first search through the known rooms starting at from.
+// Rooms filled as dead ends are never entered.
+func (m *Map) routes(from mazelib.Coordinate) *routes {
+	r := &routes{
+		dist: map[mazelib.Coordinate]int{from: 0},
+		prev: map[mazelib.Coordinate]mazelib.Coordinate{},
+		step: map[mazelib.Coordinate]mazelib.Direction{},
+	}
+	queue := []mazelib.Coordinate{from}
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, d := range mazelib.Directions {
+			n := c.Move(d)
+			if _, seen := r.dist[n]; seen || !m.Open(c, d) || !m.Known(n) || m.dead[n] {
+				continue
+			}
+			r.dist[n] = r.dist[c] + 1
+			r.prev[n] = c
+			r.step[n] = d
+			queue = append(queue, n)
+		}
+	}
+	return r
+}
+
+// Returns the directions to walk from the origin of the search to reach to,
+// or nil if to can't be reached.
+func (r *routes) pathTo(to mazelib.Coordinate) []mazelib.Direction {
+	if _, ok := r.dist[to]; !ok {
+		return nil
+	}
+	path := make([]mazelib.Direction, r.dist[to])
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i] = r.step[to]
+		to = r.prev[to]
+	}
+	return path
+}
+
+// frontier is an exit of a known room leading into an unseen room
+type frontier struct {
+	room mazelib.Coordinate
+	dir  mazelib.Direction
+}
+
+// Returns every exit of the known maze leading into unseen rooms, sorted by
+// room and direction so the same map always has them in the same order
+func (m *Map) frontiers() []frontier {
+	var f []frontier
+	for c := range m.rooms {
+		if m.dead[c] {
+			continue
+		}
+		for _, d := range m.Unexplored(c) {
+			f = append(f, frontier{c, d})
+		}
+	}
+	sort.Slice(f, func(i, j int) bool {
+		a, b := f[i].room, f[j].room
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return f[i].dir < f[j].dir
+	})
+	return f
+}
+
+// PathToFrontier finds the shortest path through known rooms to the nearest
+// room which still has unexplored exits. Rooms filled as dead ends are never
+// entered.
+// Returns nil if there is nothing left to explore.
+func (m *Map) PathToFrontier(from mazelib.Coordinate) []mazelib.Direction {
+	r := m.routes(from)
+	var best []mazelib.Direction
+	for _, f := range m.frontiers() {
+		if f.room == from {
+			continue
+		}
+		if p := r.pathTo(f.room); p != nil && (best == nil || len(p) < len(best)) {
+			best = p
+		}
+	}
+	return best
+}
+
+// Render draws the known part of the maze in the style of mazelib.PrintMaze.
+// Icarus is shown as @, the start as ⏀ and the unseen room he is heading
+// for, the closest place the treasure might be, as ⏃. Other unseen rooms
+// next to known ones are shown as ? and rooms filled as dead ends as a dot.
+func (m *Map) Render(pos mazelib.Coordinate) string {
+	minX, maxX, minY, maxY := 0, 0, 0, 0
+	for c := range m.rooms {
+		if c.X < minX {
+			minX = c.X
+		}
+		if c.X > maxX {
+			maxX = c.X
+		}
+		if c.Y < minY {
+			minY = c.Y
+		}
+		if c.Y > maxY {
+			maxY = c.Y
+		}
+	}
+	// leave room for the frontier around the known rooms
+	minX, maxX, minY, maxY = minX-1, maxX+1, minY-1, maxY+1
+
+	frontier := map[mazelib.Coordinate]bool{}
+	for _, f := range m.frontiers() {
+		frontier[f.room.Move(f.dir)] = true
+	}
+	var target mazelib.Coordinate
+	hasTarget := false
+	if dirs := m.Unexplored(pos); len(dirs) > 0 {
+		target, hasTarget = pos.Move(dirs[0]), true
+	} else if path := m.PathToFrontier(pos); path != nil {
+		c := pos
+		for _, d := range path {
+			c = c.Move(d)
+		}
+		target, hasTarget = c.Move(m.Unexplored(c)[0]), true
+	}
+
+	// walls are drawn below and to the right of every room, so the walls
+	// of the known rooms facing the frontier have to come from both sides
+	wall := func(c mazelib.Coordinate, dir mazelib.Direction) bool {
+		n := c.Move(dir)
+		return (m.Known(c) && !m.Open(c, dir)) || (m.Known(n) && !m.Open(n, dir.Opposite()))
+	}
+
+	var b strings.Builder
+	for y := minY; y <= maxY; y++ {
+		b.WriteString(" ")
+		for x := minX; x <= maxX; x++ {
+			c := mazelib.Coordinate{X: x, Y: y}
+
+			floor := " "
+			if wall(c, mazelib.S) {
+				floor = "_"
+			}
+
+			marker := floor
+			switch {
+			case c == pos:
+				marker = "@"
+			case c == mazelib.Coordinate{}:
+				marker = "⏀"
+			case hasTarget && c == target:
+				marker = "⏃"
+			case m.dead[c]:
+				marker = "."
+			case frontier[c]:
+				marker = "?"
+			}
+			b.WriteString(marker + floor)
+
+			if wall(c, mazelib.E) {
+				b.WriteString("|")
+			} else {
+				b.WriteString(floor)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
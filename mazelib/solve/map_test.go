@@ -0,0 +1,83 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package solve
+
+import (
+	"math/rand"
+	"testing"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/gen"
+	"bitbucket.org/mannih/gc6/mazelib/mazetest"
+)
+
+// checkedFill is a strategy which checks the rooms FillDeadEnds filled
+// before every step against filling every known room until nothing changes
+type checkedFill struct {
+	Strategy
+	t    *testing.T
+	dead map[mazelib.Coordinate]bool
+}
+
+func (s *checkedFill) Next(m *Map, pos mazelib.Coordinate) ([]mazelib.Direction, bool) {
+	for changed := true; changed; {
+		changed = false
+		for c := range m.rooms {
+			if c == pos || s.dead[c] || len(m.Unexplored(c)) > 0 {
+				continue
+			}
+			exits := 0
+			for _, d := range mazelib.Directions {
+				if m.Open(c, d) && !s.dead[c.Move(d)] {
+					exits++
+				}
+			}
+			if exits <= 1 {
+				s.dead[c] = true
+				changed = true
+			}
+		}
+	}
+	if len(m.dead) != len(s.dead) {
+		s.t.Fatalf("at %v %d rooms are filled, want %d", pos, len(m.dead), len(s.dead))
+	}
+	for c := range s.dead {
+		if !m.dead[c] {
+			s.t.Fatalf("at %v %v isn't filled", pos, c)
+		}
+	}
+	return s.Strategy.Next(m, pos)
+}
+
+// Filling from the rooms which changed fills the same rooms as filling all
+// of them over and over
+func TestFillDeadEnds(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		for _, name := range gen.Names() {
+			rng := rand.New(rand.NewSource(seed))
+			l := mazelib.Layout{Width: 20, Height: 15, Walls: gen.Generators[name](rng, 20, 15), Treasure: mazelib.Coordinate{X: 19, Y: 14}}
+			m := mazetest.MustNew(l)
+			strat := &checkedFill{Strategy: &DFS{Rand: rng}, t: t, dead: map[mazelib.Coordinate]bool{}}
+			stats, err := Solve(MazeMover{Maze: m}, strat, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !stats.Solved {
+				t.Errorf("%s maze of seed %d isn't solved", name, seed)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package solve
+
+import "bitbucket.org/mannih/gc6/mazelib"
+
+// MazeMover plays a maze of this process, without any server. Icarus awakes
+// wherever the maze has him, and finding the treasure doesn't move him to
+// another maze.
+type MazeMover struct {
+	Maze mazelib.MazeI
+}
+
+func (mm MazeMover) Awake() (mazelib.Survey, error) {
+	return mm.Maze.LookAround()
+}
+
+func (mm MazeMover) Walk(directions []mazelib.Direction) ([]mazelib.Survey, error) {
+	var surveys []mazelib.Survey
+	for _, d := range directions {
+		if err := mm.move(d); err != nil {
+			if _, ok := err.(*mazelib.ReplyError); ok {
+				return surveys, err
+			}
+			code := mazelib.ErrorCode(err)
+			if code == "" {
+				// whatever else the maze refuses to walk through is a wall
+				code = mazelib.ErrCodeWallHit
+			}
+			return surveys, &mazelib.ReplyError{Code: code, Message: err.Error()}
+		}
+		x, y := mm.Maze.Icarus()
+		if r, err := mm.Maze.GetRoom(x, y); err == nil && r.Treasure {
+			return surveys, mazelib.ErrVictory
+		}
+		s, err := mm.Maze.LookAround()
+		if err != nil {
+			return surveys, err
+		}
+		surveys = append(surveys, s)
+	}
+	return surveys, nil
+}
+
+func (mm MazeMover) move(d mazelib.Direction) error {
+	switch d {
+	case mazelib.N:
+		return mm.Maze.MoveUp()
+	case mazelib.S:
+		return mm.Maze.MoveDown()
+	case mazelib.W:
+		return mm.Maze.MoveLeft()
+	case mazelib.E:
+		return mm.Maze.MoveRight()
+	}
+	return &mazelib.ReplyError{Code: mazelib.ErrCodeInvalidDirection, Message: "invalid direction " + d.String()}
+}
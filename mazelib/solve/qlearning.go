@@ -0,0 +1,185 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package solve
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"sync"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Experimental reinforcement learning solver.
+//
+// Whenever Icarus stands in a room with unexplored exits he has to decide
+// which one to take. The decision is learned with tabular Q-learning over a
+// very small state: what lies in each direction (a wall, a room he knows
+// or the unknown) and the direction he came in from.
+// Every step costs a point and finding the treasure earns a reward.
+// Walking back to the closest unexplored room is not learned, Icarus simply
+// takes the shortest path he knows.
+//
+// The table is shared by all mazes of a run and saved to disk at the end,
+// so Icarus keeps learning across runs.
+
+const (
+	qAlpha         = 0.1
+	qGamma         = 0.9
+	qVictoryReward = 100
+)
+
+// QTable maps states to the learned value of each direction
+type QTable struct {
+	sync.Mutex
+	Values map[string]map[mazelib.Direction]float64 `json:"values"`
+}
+
+// LoadQTable loads a table from disk. A missing file gives an empty table.
+func LoadQTable(path string) (*QTable, error) {
+	t := &QTable{Values: map[string]map[mazelib.Direction]float64{}}
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, t); err != nil {
+		return nil, err
+	}
+	if t.Values == nil {
+		t.Values = map[string]map[mazelib.Direction]float64{}
+	}
+	return t, nil
+}
+
+// Save writes the table to disk
+func (t *QTable) Save(path string) error {
+	t.Lock()
+	defer t.Unlock()
+	contents, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, contents, 0644)
+}
+
+// Returns the best of dirs in the given state and its value
+func (t *QTable) best(rng *rand.Rand, state string, dirs []mazelib.Direction) (mazelib.Direction, float64) {
+	t.Lock()
+	defer t.Unlock()
+	best, value := mazelib.Direction(0), math.Inf(-1)
+	for _, d := range shuffle(rng, dirs) {
+		if v := t.Values[state][d]; v > value {
+			best, value = d, v
+		}
+	}
+	return best, value
+}
+
+// Moves the value of taking dir in state towards the observed return
+func (t *QTable) update(state string, dir mazelib.Direction, target float64) {
+	t.Lock()
+	defer t.Unlock()
+	if t.Values[state] == nil {
+		t.Values[state] = map[mazelib.Direction]float64{}
+	}
+	t.Values[state][dir] += qAlpha * (target - t.Values[state][dir])
+}
+
+// QLearning picks the unexplored exits by the values learned in the table,
+// and one at random with a probability of epsilon
+type QLearning struct {
+	Table   *QTable
+	Epsilon float64
+	// where the exits are drawn from, seeded by the clock if not set
+	Rand *rand.Rand
+	last mazelib.Direction
+	// length of the route handed out last
+	walked int
+
+	// the last decision, waiting for its outcome
+	pending bool
+	state   string
+	action  mazelib.Direction
+	reward  float64
+}
+
+func (s *QLearning) Next(m *Map, pos mazelib.Coordinate) ([]mazelib.Direction, bool) {
+	if s.pending {
+		s.reward -= float64(s.walked)
+	}
+
+	dirs := m.Unexplored(pos)
+	if len(dirs) == 0 {
+		path := m.PathToFrontier(pos)
+		if path == nil {
+			return nil, false
+		}
+		s.last, s.walked = path[len(path)-1], len(path)
+		return path, true
+	}
+
+	rng := seeded(&s.Rand)
+	state := qState(m, pos, s.last)
+	best, value := s.Table.best(rng, state, dirs)
+	if s.pending {
+		s.Table.update(s.state, s.action, s.reward+qGamma*value)
+	}
+
+	d := best
+	if rng.Float64() < s.Epsilon {
+		d = shuffle(rng, dirs)[0]
+	}
+	s.pending, s.state, s.action, s.reward = true, state, d, 0
+	s.last, s.walked = d, 1
+	return []mazelib.Direction{d}, true
+}
+
+// Victory is called once Icarus found the treasure
+func (s *QLearning) Victory() {
+	if s.pending {
+		s.Table.update(s.state, s.action, s.reward+qVictoryReward)
+		s.pending = false
+	}
+}
+
+// Describes what Icarus sees around him: for every direction a wall (w),
+// a room he already knows (k) or the unknown (u), followed by the direction
+// he came in.
+func qState(m *Map, pos mazelib.Coordinate, last mazelib.Direction) string {
+	state := ""
+	for _, d := range mazelib.Directions {
+		switch {
+		case !m.Open(pos, d):
+			state += "w"
+		case m.Known(pos.Move(d)):
+			state += "k"
+		default:
+			state += "u"
+		}
+	}
+	state += ":"
+	if last != 0 {
+		// before the first step he didn't come from anywhere
+		state += last.String()
+	}
+	return state
+}
@@ -0,0 +1,163 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package solve
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// Mover is what Icarus plays against: a daedalus server, or a maze of this
+// process
+type Mover interface {
+	// places Icarus in a new maze and returns the survey of the room he awoke in
+	Awake() (mazelib.Survey, error)
+	// walks Icarus along a route and returns the survey of every room he
+	// reached. If a step fails its error is returned and the rest of the
+	// route isn't walked. Reaching the treasure returns mazelib.ErrVictory
+	// for the step that got there, the server refusing a step a
+	// *mazelib.ReplyError.
+	Walk(directions []mazelib.Direction) ([]mazelib.Survey, error)
+}
+
+// Stats is what Icarus keeps track of while solving a single maze
+type Stats struct {
+	Solved bool `json:"solved"`
+	Steps  int  `json:"steps"`
+	// steps back into rooms he had already been in
+	Backtracks int `json:"backtracks"`
+	// moves the server refused
+	WallBumps int           `json:"wall_bumps"`
+	Duration  time.Duration `json:"duration"`
+	// rooms of the maze he never saw
+	Unexplored int `json:"unexplored"`
+}
+
+// Options are how Solve plays
+type Options struct {
+	// steps after which Icarus gives up on the maze, 0 for no limit
+	MaxSteps int
+	// the rooms of the maze, to count those left unexplored, 0 if unknown
+	Rooms int
+	// called with the map after every step, to watch Icarus as he walks
+	Watch func(m *Map, pos mazelib.Coordinate)
+	// where Solve tells how it went, nothing is logged without one
+	Log *slog.Logger
+}
+
+// Solve awakes Icarus in a new maze and walks him through it the way the
+// strategy decides until he finds the treasure, gives up or the mover won't
+// let him go on. Returns an error if the mover failed with anything but
+// mazelib.ErrVictory or a *mazelib.ReplyError, like a lost connection.
+func Solve(mv Mover, strat Strategy, opts Options) (stats Stats, err error) {
+	log := opts.Log
+	if log == nil {
+		log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	started := time.Now()
+	s, err := mv.Awake() // Need to start with waking up to initialize a new maze
+	if err != nil {
+		return stats, err
+	}
+
+	// Icarus keeps a map of every room he has seen and lets the strategy
+	// decide where to go next based on it.
+	m := NewMap()
+	pos := mazelib.Coordinate{}
+	m.Record(pos, s)
+
+	defer func() {
+		stats.Duration = time.Since(started)
+		if opts.Rooms <= 0 {
+			return
+		}
+		stats.Unexplored = opts.Rooms - len(m.rooms)
+		if stats.Solved {
+			// the treasure room never makes it onto the map
+			stats.Unexplored--
+		}
+	}()
+
+	budget := opts.MaxSteps
+	for {
+		if budget > 0 && stats.Steps >= budget {
+			// leave this one behind, the next awake will give us a new maze
+			log.Info("giving up", "steps", stats.Steps)
+			return stats, nil
+		}
+
+		m.FillDeadEnds(pos)
+
+		route, ok := strat.Next(m, pos)
+		if !ok {
+			log.Warn("explored the whole laybrinth without finding the treasure")
+			return stats, nil
+		}
+		if budget > 0 && len(route) > budget-stats.Steps {
+			route = route[:budget-stats.Steps]
+		}
+
+		surveys, err := mv.Walk(route)
+		for i, s := range surveys {
+			next := pos.Move(route[i])
+			stats.Steps++
+			if m.Known(next) {
+				stats.Backtracks++
+				if m.rooms[next] != s {
+					log.Warn("Daedalus disagrees with the map about a room, trusting daedalus")
+				}
+			}
+			pos = next
+			m.Record(pos, s)
+
+			if opts.Watch != nil {
+				opts.Watch(m, pos)
+			}
+		}
+
+		var refused *mazelib.ReplyError
+		errors.As(err, &refused)
+		switch {
+		case err == nil:
+		case errors.Is(err, mazelib.ErrVictory):
+			stats.Steps++
+			if m.Known(pos.Move(route[len(surveys)])) {
+				stats.Backtracks++
+			}
+			stats.Solved = true
+			if l, ok := strat.(Learner); ok {
+				l.Victory()
+			}
+			return stats, nil
+		case refused == nil:
+			return stats, err
+		case errors.Is(err, mazelib.ErrNoMaze), refused.Code == mazelib.ErrCodeStepLimit, refused.Code == mazelib.ErrCodeTimeLimit:
+			// daedalus won't let us go on in this maze
+			log.Info(refused.Message)
+			return stats, nil
+		default:
+			// the server didn't let us move, so there must be a wall we didn't know about
+			log.Debug("bumped into a wall", "direction", route[len(surveys)], "err", err)
+			stats.WallBumps++
+			m.AddWall(pos, route[len(surveys)])
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package solve_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/mazetest"
+	"bitbucket.org/mannih/gc6/mazelib/solve"
+)
+
+// the strategies by name, each made fresh for a maze with a fixed seed
+var strategies = map[string]func() solve.Strategy{
+	"dfs": func() solve.Strategy {
+		return &solve.DFS{Rand: rand.New(rand.NewSource(1))}
+	},
+	"montecarlo": func() solve.Strategy {
+		return &solve.MonteCarlo{Temperature: 1, Rand: rand.New(rand.NewSource(1))}
+	},
+	"qlearning": func() solve.Strategy {
+		return &solve.QLearning{
+			Table:   &solve.QTable{Values: map[string]map[mazelib.Direction]float64{}},
+			Epsilon: 0.1,
+			Rand:    rand.New(rand.NewSource(1)),
+		}
+	},
+}
+
+// Every strategy finds the treasure in the fixtures, taking the only way
+// there where there is just one
+func TestSolveFixtures(t *testing.T) {
+	tests := []struct {
+		name string
+		maze func() *mazetest.Maze
+		// the steps the way to the treasure forces, 0 if there's a choice
+		steps int
+		// the most steps it may take otherwise, twice the rooms of the maze
+		most int
+	}{
+		{name: "corridor", maze: func() *mazetest.Maze { return mazetest.Corridor(12) }, steps: 11},
+		{name: "spiral", maze: func() *mazetest.Maze { return mazetest.Spiral(6) }, steps: 35},
+		{name: "comb", maze: func() *mazetest.Maze { return mazetest.Comb(6, 4) }, most: 60},
+		{name: "braided", maze: func() *mazetest.Maze { return mazetest.Braided(6, 5) }, most: 60},
+	}
+	for _, tt := range tests {
+		for name, strat := range strategies {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				m := tt.maze()
+				stats, err := solve.Solve(solve.MazeMover{Maze: m}, strat(), solve.Options{MaxSteps: 1000, Rooms: m.Rooms()})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !stats.Solved {
+					t.Fatalf("gave up after %d steps", stats.Steps)
+				}
+				if stats.Steps != m.Steps {
+					t.Errorf("counted %d steps, the maze %d", stats.Steps, m.Steps)
+				}
+				if stats.WallBumps != 0 {
+					t.Errorf("bumped into %d walls of rooms he had surveyed", stats.WallBumps)
+				}
+				if tt.steps > 0 {
+					if stats.Steps != tt.steps || stats.Backtracks != 0 || stats.Unexplored != 0 {
+						t.Errorf("took %d steps with %d backtracks, leaving %d rooms unexplored, want %d steps with none",
+							stats.Steps, stats.Backtracks, stats.Unexplored, tt.steps)
+					}
+				} else if stats.Steps > tt.most {
+					t.Errorf("took %d steps, no more than %d expected", stats.Steps, tt.most)
+				}
+			})
+		}
+	}
+}
+
+// A maze without a treasure to find is given up on once it's all explored
+func TestSolveSingleRoom(t *testing.T) {
+	for name, strat := range strategies {
+		t.Run(name, func(t *testing.T) {
+			stats, err := solve.Solve(solve.MazeMover{Maze: mazetest.SingleRoom()}, strat(), solve.Options{Rooms: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Solved || stats.Steps != 0 || stats.Unexplored != 0 {
+				t.Errorf("got %+v, want an unsolved maze without any steps", stats)
+			}
+		})
+	}
+}
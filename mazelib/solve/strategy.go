@@ -0,0 +1,200 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package solve
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+)
+
+// A Strategy decides which way Icarus walks next, based on what he knows
+// about the laybrinth so far.
+// It returns the route Icarus should take, which may lead through any number
+// of rooms he already knows but has to end as soon as it enters the unknown.
+// Returns false if there is nowhere left to explore.
+type Strategy interface {
+	Next(m *Map, pos mazelib.Coordinate) ([]mazelib.Direction, bool)
+}
+
+// Strategies which learn from their results are told when Icarus found the treasure
+type Learner interface {
+	Victory()
+}
+
+// DFS is a randomized depth first search.
+// As long as the room Icarus is in has unexplored exits he picks one, weighed
+// by the exploration bias, otherwise he walks back to the closest room which
+// still has some.
+type DFS struct {
+	Bias Bias
+	// where the ties are broken from, seeded by the clock if not set
+	Rand *rand.Rand
+	last mazelib.Direction
+}
+
+func (s *DFS) Next(m *Map, pos mazelib.Coordinate) ([]mazelib.Direction, bool) {
+	if dirs := m.Unexplored(pos); len(dirs) > 0 {
+		s.last = s.Bias.pick(seeded(&s.Rand), m, pos, s.last, dirs)
+		return []mazelib.Direction{s.last}, true
+	}
+	if path := m.PathToFrontier(pos); path != nil {
+		s.last = path[len(path)-1]
+		return path, true
+	}
+	return nil, false
+}
+
+// Bias weighs the exits Icarus can choose between.
+// With every weight at zero all exits are equally likely.
+type Bias struct {
+	// bonus for walking on in the direction he came from
+	Straight float64
+	// bonus per room he gets closer to the center of the maze
+	Center float64
+	// bonus for turning clockwise, which makes him spiral outwards
+	Spiral float64
+}
+
+// Picks the best scoring of dirs leading out of pos, after Icarus walked in
+// going last. Ties are broken at random.
+func (b Bias) pick(rng *rand.Rand, m *Map, pos mazelib.Coordinate, last mazelib.Direction, dirs []mazelib.Direction) mazelib.Direction {
+	dirs = shuffle(rng, dirs)
+	best, bestScore := dirs[0], math.Inf(-1)
+	for _, d := range dirs {
+		if sc := b.score(m, pos, last, d); sc > bestScore {
+			best, bestScore = d, sc
+		}
+	}
+	return best
+}
+
+func (b Bias) score(m *Map, pos mazelib.Coordinate, last, d mazelib.Direction) float64 {
+	score := 0.0
+	if d == last {
+		score += b.Straight
+	}
+	if d == last.Clockwise() {
+		score += b.Spiral
+	}
+	if b.Center != 0 {
+		cx, cy := m.Center()
+		n := pos.Move(d)
+		closer := distance(float64(pos.X), float64(pos.Y), cx, cy) - distance(float64(n.X), float64(n.Y), cx, cy)
+		score += b.Center * closer
+	}
+	return score
+}
+
+func distance(x1, y1, x2, y2 float64) float64 {
+	return math.Abs(x1-x2) + math.Abs(y1-y2)
+}
+
+// MonteCarlo is Monte Carlo exploration.
+// Every exit into the unknown gets an expected value: the further it is from
+// the start and the more junctions lie on the way there, the more promising
+// it is. Walking there costs a step per room. One of the exits is then drawn
+// with a probability following a softmax over value minus cost, and Icarus
+// walks there before drawing again.
+// A low temperature makes Icarus greedy, a high one makes him wander.
+type MonteCarlo struct {
+	Temperature float64
+	// where the exits are drawn from, seeded by the clock if not set
+	Rand *rand.Rand
+}
+
+func (s *MonteCarlo) Next(m *Map, pos mazelib.Coordinate) ([]mazelib.Direction, bool) {
+	here := m.routes(pos)
+	start := m.routes(mazelib.Coordinate{})
+
+	var candidates []frontier
+	var scores []float64
+	for _, f := range m.frontiers() {
+		cost, ok := here.dist[f.room]
+		if !ok {
+			continue
+		}
+		value := float64(start.dist[f.room]) + float64(junctionsOnPath(m, start, f.room))
+		candidates = append(candidates, f)
+		scores = append(scores, value-float64(cost))
+	}
+	if len(candidates) == 0 {
+		return nil, false
+	}
+
+	f := candidates[softmaxPick(seeded(&s.Rand), scores, s.Temperature)]
+	return append(here.pathTo(f.room), f.dir), true
+}
+
+// Counts the rooms with more than two exits on the way from the origin of r to c
+func junctionsOnPath(m *Map, r *routes, c mazelib.Coordinate) int {
+	n := 0
+	for r.dist[c] > 0 {
+		c = r.prev[c]
+		if m.rooms[c].ExitCount() > 2 {
+			n++
+		}
+	}
+	return n
+}
+
+// Draws an index with a probability proportional to exp(score/temperature).
+// A temperature of zero or less always picks the best score.
+func softmaxPick(rng *rand.Rand, scores []float64, temperature float64) int {
+	best := 0
+	for i, sc := range scores {
+		if sc > scores[best] {
+			best = i
+		}
+	}
+	if temperature <= 0 {
+		return best
+	}
+
+	weights := make([]float64, len(scores))
+	total := 0.0
+	for i, sc := range scores {
+		weights[i] = math.Exp((sc - scores[best]) / temperature)
+		total += weights[i]
+	}
+	r := rng.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r < 0 {
+			return i
+		}
+	}
+	return best
+}
+
+// Returns the random numbers *r, after seeding them by the clock if there are none
+func seeded(r **rand.Rand) *rand.Rand {
+	if *r == nil {
+		*r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return *r
+}
+
+func shuffle(rng *rand.Rand, p []mazelib.Direction) []mazelib.Direction {
+	temp := make([]mazelib.Direction, len(p))
+	t := rng.Perm(len(p))
+	for i, j := range t {
+		temp[i] = p[j]
+	}
+	return temp
+}
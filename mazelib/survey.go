@@ -0,0 +1,65 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+// HasExit tells whether there is no wall in the direction.
+// It doesn't know where the maze ends, the outer walls are up to the maze.
+func (s Survey) HasExit(d Direction) bool {
+	switch d {
+	case N:
+		return !s.Top
+	case S:
+		return !s.Bottom
+	case W:
+		return !s.Left
+	case E:
+		return !s.Right
+	}
+	return false
+}
+
+// Exits returns the directions without a wall, in the order of Directions
+func (s Survey) Exits() []Direction {
+	var dirs []Direction
+	for _, d := range Directions {
+		if s.HasExit(d) {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs
+}
+
+// ExitCount returns how many directions have no wall
+func (s Survey) ExitCount() int {
+	n := 0
+	for _, d := range Directions {
+		if s.HasExit(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// IsDeadEnd tells whether the only way out of the room is back, after
+// walking in going came
+func (s Survey) IsDeadEnd(came Direction) bool {
+	for _, d := range Directions {
+		if d != came.Opposite() && s.HasExit(d) {
+			return false
+		}
+	}
+	return true
+}
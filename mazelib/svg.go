@@ -0,0 +1,160 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+)
+
+// RenderOptions are how a maze is drawn as an image
+type RenderOptions struct {
+	// the size of a room in pixels, 16 if not set
+	RoomSize int
+	// the colors, DefaultPalette if not set
+	Palette Palette
+	// the paths drawn over the maze, through the middle of their rooms
+	Overlays []Overlay
+	// draw Icarus where he is in the maze
+	Icarus bool
+}
+
+// Palette is the colors of a maze image
+type Palette struct {
+	Background color.RGBA
+	Wall       color.RGBA
+	Start      color.RGBA
+	Treasure   color.RGBA
+	Icarus     color.RGBA
+}
+
+// DefaultPalette is what mazes are drawn in unless told otherwise
+var DefaultPalette = Palette{
+	Background: color.RGBA{0xff, 0xff, 0xff, 0xff},
+	Wall:       color.RGBA{0x22, 0x22, 0x22, 0xff},
+	Start:      color.RGBA{0x2e, 0xa0, 0x43, 0xff},
+	Treasure:   color.RGBA{0xe3, 0xb3, 0x41, 0xff},
+	Icarus:     color.RGBA{0xd0, 0x00, 0x00, 0xff},
+}
+
+// Overlay is a path drawn over a maze
+type Overlay struct {
+	Path  []Coordinate
+	Color color.RGBA
+}
+
+// Returns the options with the defaults filled in
+func (o RenderOptions) withDefaults() RenderOptions {
+	if o.RoomSize <= 0 {
+		o.RoomSize = 16
+	}
+	if o.Palette == (Palette{}) {
+		o.Palette = DefaultPalette
+	}
+	return o
+}
+
+// RenderSVG draws the maze as an SVG image, with the start and treasure
+// filled in and the overlays on top of it
+func RenderSVG(m MazeI, opts RenderOptions) ([]byte, error) {
+	opts = opts.withDefaults()
+	size, p := opts.RoomSize, opts.Palette
+
+	var b bytes.Buffer
+	w, h := m.Width()*size+2, m.Height()*size+2
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="-1 -1 %d %d">`+"\n", w, h, w, h)
+	fmt.Fprintf(&b, `<rect x="-1" y="-1" width="%d" height="%d" fill="%s"/>`+"\n", w, h, hexColor(p.Background))
+
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			r, err := m.GetRoom(x, y)
+			if err != nil {
+				return nil, err
+			}
+			var fill color.RGBA
+			switch {
+			case r.Treasure:
+				fill = p.Treasure
+			case r.Start:
+				fill = p.Start
+			default:
+				continue
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+				x*size, y*size, size, size, hexColor(fill))
+		}
+	}
+
+	for _, o := range opts.Overlays {
+		if len(o.Path) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="%d" points="`, hexColor(o.Color), size/4)
+		for _, c := range o.Path {
+			fmt.Fprintf(&b, "%d,%d ", c.X*size+size/2, c.Y*size+size/2)
+		}
+		b.WriteString(`"/>` + "\n")
+	}
+
+	rooms := make([][]Survey, m.Height())
+	for y := range rooms {
+		rooms[y] = make([]Survey, m.Width())
+		for x := range rooms[y] {
+			s, err := m.Discover(x, y)
+			if err != nil {
+				return nil, err
+			}
+			rooms[y][x] = s
+		}
+	}
+	fmt.Fprintf(&b, `<g stroke="%s" stroke-width="2" stroke-linecap="square">`+"\n", hexColor(p.Wall))
+	for y, row := range rooms {
+		for x, s := range row {
+			x0, y0, x1, y1 := x*size, y*size, (x+1)*size, (y+1)*size
+			line := func(ax, ay, bx, by int) {
+				fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", ax, ay, bx, by)
+			}
+			// every wall is drawn once, by the room above or left of it
+			// unless only the room on the other side has it
+			if s.Top && (y == 0 || !rooms[y-1][x].Bottom) {
+				line(x0, y0, x1, y0)
+			}
+			if s.Left && (x == 0 || !row[x-1].Right) {
+				line(x0, y0, x0, y1)
+			}
+			if s.Bottom {
+				line(x0, y1, x1, y1)
+			}
+			if s.Right {
+				line(x1, y0, x1, y1)
+			}
+		}
+	}
+	b.WriteString("</g>\n")
+
+	if opts.Icarus {
+		x, y := m.Icarus()
+		fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", x*size+size/2, y*size+size/2, size/3, hexColor(p.Icarus))
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes(), nil
+}
+
+// Returns the color as used in SVG and HTML
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,107 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Mazes are written as text the way PrintMaze draws them, which is easy to
+// edit by hand. The first line has a _ above every room with a wall to the
+// top. Every room of the rows below takes three characters: its mark, _ if
+// it has a wall to the bottom and | if it has one to the right. A | in
+// front of the first room is the wall to its left. The treasure is marked
+// ⏃, ⏅ or T and the start ⏀, ⏂ or S, anything else is an empty room.
+// Walls between two rooms are taken from the room above or left of them.
+// Reading stops at the first line that isn't a row, like the one telling the
+// seed below a maze printed by generate.
+
+// ParseMaze reads the layout of a maze written as text by FprintMaze
+func ParseMaze(r io.Reader) (Layout, error) {
+	var l Layout
+	var lines [][]rune
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := []rune(strings.TrimRight(scanner.Text(), "\r"))
+		if len(lines) > 0 && (len(line) == 0 || (line[0] != '|' && line[0] != ' ')) {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return l, err
+	}
+	if len(lines) < 2 {
+		return l, fmt.Errorf("a maze needs a line of walls on top and at least a row of rooms")
+	}
+
+	// editors like to cut off the spaces at the end of a line
+	l.Width = (len(lines[0]) - 1) / 3
+	for _, line := range lines[1:] {
+		if w := (len(line) - 1) / 3; w > l.Width {
+			l.Width = w
+		}
+	}
+	if l.Width < 1 {
+		return l, fmt.Errorf("a maze needs at least a room in a row")
+	}
+	l.Height = len(lines) - 1
+	at := func(line []rune, i int) rune {
+		if i < len(line) {
+			return line[i]
+		}
+		return ' '
+	}
+
+	starts, treasures := 0, 0
+	l.Walls = make([][]Survey, l.Height)
+	for y := range l.Walls {
+		line := lines[y+1]
+		l.Walls[y] = make([]Survey, l.Width)
+		for x := range l.Walls[y] {
+			s := &l.Walls[y][x]
+			if y == 0 {
+				s.Top = at(lines[0], 3*x+1) == '_' || at(lines[0], 3*x+2) == '_'
+			} else {
+				s.Top = l.Walls[y-1][x].Bottom
+			}
+			if x == 0 {
+				s.Left = at(line, 0) == '|'
+			} else {
+				s.Left = l.Walls[y][x-1].Right
+			}
+			s.Bottom = at(line, 3*x+2) == '_'
+			s.Right = at(line, 3*x+3) == '|'
+
+			c := Coordinate{x, y}
+			switch at(line, 3*x+1) {
+			case '⏃', '⏅', 'T':
+				l.Treasure = c
+				treasures++
+			case '⏀', '⏂', 'S':
+				l.Start = c
+				starts++
+			}
+		}
+	}
+	if starts != 1 || treasures != 1 {
+		return l, fmt.Errorf("a maze needs a single start and treasure, not %d and %d", starts, treasures)
+	}
+	return l, nil
+}
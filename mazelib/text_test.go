@@ -0,0 +1,59 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"bitbucket.org/mannih/gc6/mazelib"
+	"bitbucket.org/mannih/gc6/mazelib/mazetest"
+)
+
+// Whatever ParseMaze makes of a text is a whole maze with a start and a
+// treasure, which reads the same again once printed
+func FuzzParseMaze(f *testing.F) {
+	f.Add(mazelib.SprintMaze(mazetest.Corridor(4)))
+	f.Add(mazelib.SprintMaze(mazetest.Spiral(5)))
+	f.Add(mazelib.SprintMaze(mazetest.Braided(6, 4)) + "binarytree maze with seed 1\n")
+	f.Add("_______\r\n|S  T_|\r\n|__|__|\r\n")
+	// open to the top and the left, and cut off by an editor at the end
+	f.Add("    ___\n|S  T_|\n  _|__|")
+	f.Add("_\n|")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, text string) {
+		l, err := mazelib.ParseMaze(strings.NewReader(text))
+		if err != nil {
+			return
+		}
+		if err := l.Whole(); err != nil {
+			t.Fatalf("read a maze that %v", err)
+		}
+		m, err := mazetest.New(l)
+		if err != nil {
+			t.Fatalf("can't build the maze read: %v", err)
+		}
+		printed := mazelib.SprintMaze(m)
+		again, err := mazelib.ParseMaze(strings.NewReader(printed))
+		if err != nil {
+			t.Fatalf("can't read the maze printed:\n%s%v", printed, err)
+		}
+		if !reflect.DeepEqual(l, again) {
+			t.Fatalf("the maze read again differs:\n%s%+v\n%+v", printed, l, again)
+		}
+	})
+}
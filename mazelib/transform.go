@@ -0,0 +1,76 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+// Rotate returns a copy of the layout turned a quarter clockwise,
+// so the top row becomes the right column
+func (l Layout) Rotate() Layout {
+	return l.transform(l.Height, l.Width,
+		func(c Coordinate) Coordinate { return Coordinate{l.Height - 1 - c.Y, c.X} },
+		func(s Survey) Survey { return Survey{Top: s.Left, Right: s.Top, Bottom: s.Right, Left: s.Bottom} })
+}
+
+// Mirror returns a copy of the layout flipped left to right
+func (l Layout) Mirror() Layout {
+	return l.transform(l.Width, l.Height,
+		func(c Coordinate) Coordinate { return Coordinate{l.Width - 1 - c.X, c.Y} },
+		func(s Survey) Survey { return Survey{Top: s.Top, Right: s.Left, Bottom: s.Bottom, Left: s.Right} })
+}
+
+// Flip returns a copy of the layout flipped upside down
+func (l Layout) Flip() Layout {
+	return l.transform(l.Width, l.Height,
+		func(c Coordinate) Coordinate { return Coordinate{c.X, l.Height - 1 - c.Y} },
+		func(s Survey) Survey { return Survey{Top: s.Bottom, Right: s.Right, Bottom: s.Top, Left: s.Left} })
+}
+
+// Transpose returns a copy of the layout mirrored along the diagonal from
+// the top left corner, so rows become columns
+func (l Layout) Transpose() Layout {
+	return l.transform(l.Height, l.Width,
+		func(c Coordinate) Coordinate { return Coordinate{c.Y, c.X} },
+		func(s Survey) Survey { return Survey{Top: s.Left, Right: s.Bottom, Bottom: s.Right, Left: s.Top} })
+}
+
+// Orientations returns the eight ways the layout can be turned and flipped,
+// starting with the layout itself. Mazes that are the same but for their
+// orientation have the same orientations, in a different order.
+func (l Layout) Orientations() []Layout {
+	ls := make([]Layout, 0, 8)
+	for _, o := range []Layout{l, l.Mirror()} {
+		for i := 0; i < 4; i++ {
+			ls = append(ls, o)
+			o = o.Rotate()
+		}
+	}
+	return ls
+}
+
+// Returns a new layout of the size with every room of l moved to where to
+// puts it, and its walls turned by walls
+func (l Layout) transform(width, height int, to func(Coordinate) Coordinate, walls func(Survey) Survey) Layout {
+	t := Layout{Width: width, Height: height, Walls: make([][]Survey, height), Start: to(l.Start), Treasure: to(l.Treasure)}
+	for y := range t.Walls {
+		t.Walls[y] = make([]Survey, width)
+	}
+	for y, row := range l.Walls {
+		for x, s := range row {
+			c := to(Coordinate{x, y})
+			t.Walls[c.Y][c.X] = walls(s)
+		}
+	}
+	return t
+}
@@ -0,0 +1,97 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+import "fmt"
+
+// WallError is a wall that isn't where it should be: missing from the
+// outside of the maze, or there from one side of two rooms but not the other
+type WallError struct {
+	Room Coordinate
+	Dir  Direction
+	// the room has no wall to the outside of the maze
+	Outer bool
+}
+
+var sideNames = map[Direction]string{N: "top", S: "bottom", E: "right", W: "left"}
+
+func (e WallError) Error() string {
+	if e.Outer {
+		return fmt.Sprintf("room %d,%d has no wall to the %s", e.Room.X, e.Room.Y, sideNames[e.Dir])
+	}
+	n := e.Room.Move(e.Dir)
+	return fmt.Sprintf("room %d,%d and room %d,%d disagree about the wall between them", e.Room.X, e.Room.Y, n.X, n.Y)
+}
+
+// CheckWalls returns every room without a wall to the outside of the maze
+// and every pair of rooms that disagree about the wall between them, or nil
+// if all walls are fine. Pairs are reported once, from the room to the left
+// or above.
+func CheckWalls(m MazeI) []WallError {
+	var errs []WallError
+	g := GridOf(m)
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			c := Coordinate{x, y}
+			s, err := m.Discover(x, y)
+			if err != nil {
+				continue
+			}
+			for _, d := range g.Directions() {
+				n, ok := g.Neighbor(c, d)
+				if !ok {
+					if s.HasExit(d) {
+						errs = append(errs, WallError{Room: c, Dir: d, Outer: true})
+					}
+					continue
+				}
+				if d != E && d != S {
+					continue
+				}
+				if o, err := m.Discover(n.X, n.Y); err == nil && s.HasExit(d) != o.HasExit(d.Opposite()) {
+					errs = append(errs, WallError{Room: c, Dir: d})
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// RepairWalls fixes everything CheckWalls finds: the outside of the maze is
+// walled up, and a wall that is only there from one side is taken down, the
+// way a generator forgetting the other side of RmWall meant it to be.
+// Returns how many walls it fixed.
+func RepairWalls(m MazeI) int {
+	errs := CheckWalls(m)
+	for _, e := range errs {
+		r, err := m.GetRoom(e.Room.X, e.Room.Y)
+		if err != nil {
+			continue
+		}
+		if e.Outer {
+			r.AddWall(e.Dir)
+			continue
+		}
+		n := e.Room.Move(e.Dir)
+		o, err := m.GetRoom(n.X, n.Y)
+		if err != nil {
+			continue
+		}
+		r.RmWall(e.Dir)
+		o.RmWall(e.Dir.Opposite())
+	}
+	return len(errs)
+}
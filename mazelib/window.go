@@ -0,0 +1,90 @@
+// Copyright © 2015 Steve Francia <spf@spf13.com>.
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+//
+
+package mazelib
+
+// Window is the part of a maze Clip cuts out. It's a maze of its own with
+// the room at Origin of the maze as its 0,0, so it's drawn like any other.
+// The rooms keep their walls, so the window may be open on every side the
+// maze goes on at.
+type Window struct {
+	MazeI
+	Origin        Coordinate
+	width, height int
+}
+
+// Clip returns the part of the maze of at most width x height rooms with
+// the room at c in its middle, as far as the maze allows. The window is the
+// whole maze if it isn't larger than that.
+func Clip(m MazeI, c Coordinate, width, height int) *Window {
+	w := &Window{MazeI: m, width: m.Width(), height: m.Height()}
+	if width > 0 && width < w.width {
+		w.Origin.X = clamp(c.X-width/2, 0, w.width-width)
+		w.width = width
+	}
+	if height > 0 && height < w.height {
+		w.Origin.Y = clamp(c.Y-height/2, 0, w.height-height)
+		w.height = height
+	}
+	return w
+}
+
+// Returns v, but no less than lo and no more than hi
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// Whole tells whether the window shows all of the maze
+func (w *Window) Whole() bool {
+	return w.width == w.MazeI.Width() && w.height == w.MazeI.Height()
+}
+
+func (w *Window) Width() int  { return w.width }
+func (w *Window) Height() int { return w.height }
+
+func (w *Window) GetRoom(x, y int) (*Room, error) {
+	if !(Coordinate{x, y}).In(w.width, w.height) {
+		return &Room{}, ErrOutOfBounds
+	}
+	return w.MazeI.GetRoom(x+w.Origin.X, y+w.Origin.Y)
+}
+
+func (w *Window) Discover(x, y int) (Survey, error) {
+	if !(Coordinate{x, y}).In(w.width, w.height) {
+		return Survey{}, ErrOutOfBounds
+	}
+	return w.MazeI.Discover(x+w.Origin.X, y+w.Origin.Y)
+}
+
+// Icarus returns where Icarus is in the window, which may be outside of it
+func (w *Window) Icarus() (x, y int) {
+	x, y = w.MazeI.Icarus()
+	return x - w.Origin.X, y - w.Origin.Y
+}
+
+// Local returns the coordinates of the rooms in the window
+func (w *Window) Local(path []Coordinate) []Coordinate {
+	local := make([]Coordinate, len(path))
+	for i, c := range path {
+		local[i] = Coordinate{c.X - w.Origin.X, c.Y - w.Origin.Y}
+	}
+	return local
+}